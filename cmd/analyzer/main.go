@@ -0,0 +1,247 @@
+// Команда analyzer выполняет офлайн-анализ экспортов чатов без Telegram-бота:
+//
+//	analyzer [флаги] result.json [result2.json ...]
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Nikalively/telegram-export-parser/parser"
+	"go.opentelemetry.io/otel"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/charts"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/export"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/filter"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/ignorelist"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/interaction"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/metadata"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/participant"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/stats"
+	"github.com/MaxFando/tg-export-chat-analyzer/pkg/tracing"
+)
+
+func main() {
+	from := flag.String("from", "", "учитывать сообщения начиная с даты (YYYY-MM-DD)")
+	to := flag.String("to", "", "учитывать сообщения по дату включительно (YYYY-MM-DD)")
+	last := flag.Int("last", 0, "учитывать только последние N дней")
+	service := flag.Bool("service", false, "учитывать служебные сообщения (вступления, закрепы) как активность")
+	onlyText := flag.Bool("only-text", false, "учитывать только сообщения с текстом")
+	onlyReplies := flag.Bool("only-replies", false, "учитывать только ответы")
+	excludeBots := flag.Bool("exclude-bots", false, "исключить ботов из результата")
+	includeChannels := flag.Bool("include-channels", false, "считать участниками каналы и анонимных админов")
+	minMessages := flag.Int("min-messages", 1, "учитывать авторов, написавших не меньше N сообщений")
+	noMentions := flag.Bool("no-mentions", false, "не собирать упоминания")
+	excludeUsers := flag.String("exclude-users", "", "исключить пользователей (через запятую)")
+	caseSensitive := flag.Bool("case-sensitive", false, "различать регистр имён")
+	contacts := flag.Bool("contacts", false, "извлекать телефоны и email участников (персональные данные)")
+	ignoreFile := flag.String("ignore-file", "", "файл со списком игнорирования (правило на строку, ! — исключение)")
+	out := flag.String("out", "", "путь к Excel файлу; без флага список выводится в stdout")
+	csvDir := flag.String("csv-dir", "", "директория для CSV файлов с хэштегами, ссылками и доменами")
+	htmlOut := flag.String("html", "", "путь к автономному HTML отчёту")
+	chartsDir := flag.String("charts-dir", "", "директория для PNG графиков активности и рейтинга участников")
+	traceExporter := flag.String("trace", "", "экспорт спанов этапов: stdout (в stderr) или otlp (OTEL_EXPORTER_OTLP_ENDPOINT)")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: analyzer [flags] export.json [export2.json ...]")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	shutdown, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:    *traceExporter,
+		ServiceName: "tg-export-analyzer",
+		SampleRatio: 1,
+		Output:      os.Stderr,
+	})
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdown(ctx); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}()
+
+	ctx, span := otel.Tracer("github.com/MaxFando/tg-export-chat-analyzer/cmd/analyzer").Start(ctx, "analyzer.Run")
+	defer span.End()
+
+	spec := filter.Spec{
+		From:           mustParseDate(*from),
+		To:             mustParseDate(*to),
+		LastDays:       *last,
+		IncludeService: *service,
+		OnlyWithText:   *onlyText,
+		OnlyReplies:    *onlyReplies,
+	}
+	if err := spec.Validate(); err != nil {
+		log.Fatalf("Invalid filter: %v", err)
+	}
+
+	// Парсим все файлы
+	var allEvents []parser.Event
+	var indexes []metadata.Index
+	for _, path := range flag.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", path, err)
+		}
+
+		events, err := parser.ParseFile(bytes.NewReader(data), path)
+		if err != nil {
+			log.Fatalf("Failed to parse %s: %v", path, err)
+		}
+
+		index, err := metadata.Parse(data, path)
+		if err != nil {
+			log.Fatalf("Failed to parse metadata of %s: %v", path, err)
+		}
+
+		allEvents = append(allEvents, events...)
+		indexes = append(indexes, index)
+	}
+
+	index := metadata.Merge(indexes...)
+	events := spec.Apply(spec.Events(allEvents, index), index)
+
+	var ignoreList ignorelist.List
+	if *ignoreFile != "" {
+		ignoreList = mustLoadIgnoreList(*ignoreFile)
+	}
+
+	// Извлекаем участников
+	extractor := participant.New(
+		participant.WithExcludeBots(*excludeBots),
+		participant.WithIncludeChannels(*includeChannels),
+		participant.WithMinMessages(*minMessages),
+		participant.WithIncludeMentions(!*noMentions),
+		participant.WithExcludeUsers(strings.Split(*excludeUsers, ",")...),
+		participant.WithCaseSensitive(*caseSensitive),
+		participant.WithIgnoreList(ignoreList),
+		participant.WithContacts(*contacts),
+	)
+	result, err := extractor.Extract(ctx, events, index)
+	if err != nil {
+		log.Fatalf("Failed to extract participants: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Filter: %s\n", spec)
+	fmt.Fprintf(os.Stderr, "Events: %d, participants: %d, mentions: %d, channels: %d\n",
+		len(events), len(result.Participants), len(result.Mentions), len(result.Channels))
+
+	exportSvc := export.New()
+
+	if *csvDir != "" {
+		writeCSV(exportSvc, result, *csvDir)
+	}
+
+	report := export.Report{
+		Result:       result,
+		Interactions: interaction.New().Analyze(events, index),
+		Filter:       spec,
+		Activity:     stats.Compute(events, index),
+	}
+
+	if *chartsDir != "" {
+		writeCharts(report.Activity, *chartsDir)
+	}
+
+	if *htmlOut != "" {
+		data, err := exportSvc.ExportToHTML(ctx, report)
+		if err != nil {
+			log.Fatalf("Failed to export to HTML: %v", err)
+		}
+		if err := os.WriteFile(*htmlOut, data, 0600); err != nil {
+			log.Fatalf("Failed to write %s: %v", *htmlOut, err)
+		}
+	}
+
+	if *out == "" {
+		for _, msg := range exportSvc.FormatResultForTelegram(result, participant.DefaultLabels) {
+			fmt.Println(msg)
+		}
+		return
+	}
+
+	data, err := exportSvc.ExportReportToExcel(ctx, report)
+	if err != nil {
+		log.Fatalf("Failed to export to Excel: %v", err)
+	}
+
+	if err := os.WriteFile(*out, data, 0600); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+}
+
+// writeCSV сохраняет CSV таблицы хэштегов, ссылок и доменов в директорию
+func writeCSV(exportSvc *export.Service, result participant.Result, dir string) {
+	files, err := exportSvc.ExportContentCSV(result)
+	if err != nil {
+		log.Fatalf("Failed to export CSV: %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		log.Fatalf("Failed to create %s: %v", dir, err)
+	}
+
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file.Name), file.Bytes, 0600); err != nil {
+			log.Fatalf("Failed to write %s: %v", file.Name, err)
+		}
+	}
+}
+
+// writeCharts сохраняет PNG графики в директорию
+func writeCharts(activity stats.Report, dir string) {
+	images, err := charts.Render(activity, charts.FormatPNG, stats.DefaultLabels)
+	if err != nil {
+		log.Fatalf("Failed to render charts: %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		log.Fatalf("Failed to create %s: %v", dir, err)
+	}
+
+	for _, image := range images {
+		if err := os.WriteFile(filepath.Join(dir, image.Name), image.Bytes, 0600); err != nil {
+			log.Fatalf("Failed to write %s: %v", image.Name, err)
+		}
+	}
+}
+
+// mustParseDate разбирает дату из флага, пустая строка означает отсутствие ограничения
+func mustParseDate(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	date, err := time.Parse(filter.DateLayout, value)
+	if err != nil {
+		log.Fatalf("Invalid date %q: expected YYYY-MM-DD", value)
+	}
+	return date
+}
+
+// mustLoadIgnoreList читает список игнорирования из файла
+func mustLoadIgnoreList(path string) ignorelist.List {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open ignore list %s: %v", path, err)
+	}
+	defer f.Close()
+
+	list, err := ignorelist.Parse(f)
+	if err != nil {
+		log.Fatalf("Failed to parse ignore list %s: %v", path, err)
+	}
+	return list
+}
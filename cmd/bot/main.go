@@ -1,77 +1,131 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"os"
-	"strconv"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/config"
 	"github.com/MaxFando/tg-export-chat-analyzer/internal/delivery/telegram"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/quota"
+	"github.com/MaxFando/tg-export-chat-analyzer/pkg/tracing"
 )
 
 func main() {
-	// Получаем конфигурацию из переменных окружения
-	token := os.Getenv("TELEGRAM_BOT_TOKEN")
-	if token == "" {
-		log.Fatal("TELEGRAM_BOT_TOKEN environment variable not set")
+	// Конфигурация: значения по умолчанию < файл (--config, CONFIG_FILE) < переменные окружения < флаги
+	conf, flags, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
 	}
-
-	logLevel := os.Getenv("LOG_LEVEL")
-	if logLevel == "" {
-		logLevel = "info"
+	if flags.PrintConfig {
+		if perr := conf.Print(os.Stdout); perr != nil {
+			log.Fatalf("Failed to print config: %v", perr)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
-
-	tempDir := os.Getenv("TEMP_DIR")
-	if tempDir == "" {
-		tempDir = "/tmp/telegram-bot"
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
 	}
 
-	// Парсим целочисленные параметры
-	maxFiles := 10
-	if maxFilesStr := os.Getenv("MAX_FILES"); maxFilesStr != "" {
-		if v, err := strconv.Atoi(maxFilesStr); err == nil {
-			maxFiles = v
-		}
+	// Трассировка этапов обработки: TRACE_EXPORTER=none|stdout|otlp
+	traceCfg := tracing.Config{
+		Exporter:    conf.TraceExporter,
+		Endpoint:    conf.TraceEndpoint,
+		SampleRatio: conf.TraceSampleRatio,
 	}
-
-	maxFileSizeMB := 10
-	if maxFileSizeStr := os.Getenv("MAX_FILE_SIZE_MB"); maxFileSizeStr != "" {
-		if v, err := strconv.Atoi(maxFileSizeStr); err == nil {
-			maxFileSizeMB = v
-		}
+	shutdownTracing, err := tracing.Setup(context.Background(), traceCfg)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
 	}
 
-	maxTotalSizeMB := 100
-	if maxTotalStr := os.Getenv("MAX_TOTAL_SIZE_MB"); maxTotalStr != "" {
-		if v, err := strconv.Atoi(maxTotalStr); err == nil {
-			maxTotalSizeMB = v
-		}
+	// SIGTERM (docker stop) и SIGINT останавливают приём обновлений; после этого буферизованные
+	// спаны отправляются до выхода. log.Fatalf не выполняет defer, поэтому ошибки run
+	// обрабатываются только после сброса трассировки
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err = run(ctx, conf)
+	stop()
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+	if ferr := shutdownTracing(flushCtx); ferr != nil {
+		log.Printf("Failed to flush traces: %v", ferr)
 	}
+	cancel()
 
-	sessionTimeoutMin := 60
-	if timeoutStr := os.Getenv("SESSION_TIMEOUT_MINUTES"); timeoutStr != "" {
-		if v, err := strconv.Atoi(timeoutStr); err == nil {
-			sessionTimeoutMin = v
-		}
+	if err != nil {
+		log.Fatal(err)
 	}
+}
+
+// tracingFlushTimeout сколько ждать отправки буферизованных спанов при остановке
+const tracingFlushTimeout = 2 * time.Second
 
-	// Создаём бота
+// run создаёт бота и обрабатывает обновления до отмены ctx
+func run(ctx context.Context, conf config.Config) error {
 	cfg := telegram.Config{
-		Token:             token,
-		MaxFiles:          maxFiles,
-		MaxFileSizeMB:     maxFileSizeMB,
-		MaxTotalSizeMB:    maxTotalSizeMB,
-		SessionTimeoutMin: sessionTimeoutMin,
-		LogLevel:          logLevel,
-		TempDir:           tempDir,
+		Token:             conf.Token,
+		APIEndpoint:       conf.APIEndpoint,
+		MaxFiles:          conf.MaxFiles,
+		MaxFileSizeMB:     conf.MaxFileSizeMB,
+		MaxTotalSizeMB:    conf.MaxTotalSizeMB,
+		SessionTimeoutMin: conf.SessionTimeoutMin,
+		LogLevel:          conf.LogLevel,
+		LogFormat:         conf.LogFormat,
+		LogOutput:         conf.LogOutput,
+		LogSource:         conf.LogSource,
+		LogHashKey:        conf.LogHashKey,
+		TempDir:           conf.TempDir,
+		DataDir:           conf.DataDir,
+		SecureDelete:      conf.TempSecureDelete,
+		MinMessages:       conf.MinMessages,
+		IncludeMentions:   conf.IncludeMentions,
+		ExcludeUsers:      conf.ExcludeUsers,
+		CaseSensitive:     conf.CaseSensitive,
+		ListThreshold:     conf.ListThreshold,
+		ContactsEnabled:   conf.ContactsEnabled,
+		Access: telegram.AccessConfig{
+			AllowedUsers: conf.AllowedUsers,
+			AllowedChats: conf.AllowedChats,
+			Admins:       conf.AdminUsers,
+			PrivateOnly:  conf.PrivateChatsOnly,
+		},
+		Quota: quota.Limits{
+			ProcessPerHour:    conf.QuotaProcessPerHour,
+			ProcessPerDay:     conf.QuotaProcessPerDay,
+			UploadBytesPerDay: int64(conf.QuotaUploadMBPerDay) << 20,
+			CommandsPerMinute: conf.QuotaCommandsPerMin,
+			CommandBurst:      conf.QuotaCommandBurst,
+			BanAfter:          conf.BanAfterViolations,
+			BanWindow:         time.Duration(conf.BanWindowMinutes) * time.Minute,
+			BanDuration:       time.Duration(conf.BanDurationMinutes) * time.Minute,
+		},
+		Sender: telegram.SenderConfig{
+			GlobalRate: conf.SendGlobalRate,
+			ChatRate:   conf.SendChatRate,
+			ChatBurst:  conf.SendChatBurst,
+			Retries:    conf.SendRetries,
+		},
+		AdminAddr:     conf.AdminAddr,
+		MinFreeDiskMB: conf.MinFreeDiskMB,
 	}
 
 	bot, err := telegram.New(cfg)
 	if err != nil {
-		log.Fatalf("Failed to create bot: %v", err)
+		return fmt.Errorf("failed to create bot: %w", err)
 	}
 
 	// Запускаем бота
-	if err := bot.Start(); err != nil {
-		log.Fatalf("Failed to start bot: %v", err)
+	if err := bot.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start bot: %w", err)
 	}
+	return nil
 }
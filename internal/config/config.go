@@ -0,0 +1,173 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// TelegramMaxDownloadMB лимит облачного Bot API на скачивание файла ботом
+const TelegramMaxDownloadMB = 20
+
+// LocalAPIMaxDownloadMB лимит собственного сервера telegram-bot-api: файл, который пользователь
+// может отправить в Telegram
+const LocalAPIMaxDownloadMB = 2000
+
+// Config конфигурация бота. Источники по возрастанию приоритета:
+// значения по умолчанию, файл (--config или CONFIG_FILE), переменные окружения, флаги.
+type Config struct {
+	Token       string
+	APIEndpoint string
+
+	LogLevel   string
+	LogFormat  string
+	LogOutput  string
+	LogSource  bool
+	LogHashKey string
+
+	MaxFiles          int
+	MaxFileSizeMB     int
+	MaxTotalSizeMB    int
+	SessionTimeoutMin int
+	TempDir           string
+	DataDir           string
+	TempSecureDelete  bool
+
+	MinMessages     int
+	IncludeMentions bool
+	CaseSensitive   bool
+	ExcludeUsers    []string
+	ListThreshold   int
+	ContactsEnabled bool
+
+	AllowedUsers     []string
+	AllowedChats     []int64
+	AdminUsers       []int64
+	PrivateChatsOnly bool
+
+	QuotaProcessPerHour int
+	QuotaProcessPerDay  int
+	QuotaUploadMBPerDay int
+	QuotaCommandsPerMin float64
+	QuotaCommandBurst   int
+	BanAfterViolations  int
+	BanWindowMinutes    int
+	BanDurationMinutes  int
+
+	SendGlobalRate float64
+	SendChatRate   float64
+	SendChatBurst  int
+	SendRetries    int
+
+	AdminAddr     string
+	MinFreeDiskMB int
+
+	TraceExporter    string
+	TraceEndpoint    string
+	TraceSampleRatio float64
+}
+
+// Default возвращает конфигурацию по умолчанию
+func Default() Config {
+	return Config{
+		LogLevel:          "info",
+		LogFormat:         "text",
+		LogOutput:         "stderr",
+		MaxFiles:          10,
+		MaxFileSizeMB:     10,
+		MaxTotalSizeMB:    100,
+		SessionTimeoutMin: 60,
+		TempDir:           "/tmp/telegram-bot",
+		DataDir:           "/var/lib/telegram-bot",
+		MinMessages:       1,
+		IncludeMentions:   true,
+		ListThreshold:     50,
+
+		QuotaProcessPerHour: 10,
+		QuotaProcessPerDay:  50,
+		QuotaUploadMBPerDay: 1000,
+		QuotaCommandsPerMin: 30,
+		QuotaCommandBurst:   10,
+		BanAfterViolations:  20,
+		BanWindowMinutes:    10,
+		BanDurationMinutes:  60,
+
+		SendGlobalRate:   30,
+		SendChatRate:     1,
+		SendChatBurst:    3,
+		SendRetries:      3,
+		MinFreeDiskMB:    100,
+		TraceExporter:    "none",
+		TraceSampleRatio: 1,
+	}
+}
+
+// Validate проверяет значения и возвращает все найденные ошибки сразу
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Token != "", "TELEGRAM_BOT_TOKEN is required")
+	check(oneOf(c.LogLevel, "debug", "info", "warn", "error"), "LOG_LEVEL must be one of debug, info, warn, error, got %q", c.LogLevel)
+	check(oneOf(c.LogFormat, "text", "json"), "LOG_FORMAT must be text or json, got %q", c.LogFormat)
+
+	check(c.MaxFiles >= 1 && c.MaxFiles <= 100, "MAX_FILES must be between 1 and 100, got %d", c.MaxFiles)
+	if c.APIEndpoint != "" {
+		u, err := url.Parse(c.APIEndpoint)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"TELEGRAM_API_ENDPOINT must be an http(s) URL, got %q", c.APIEndpoint)
+		check(c.MaxFileSizeMB >= 1 && c.MaxFileSizeMB <= LocalAPIMaxDownloadMB,
+			"MAX_FILE_SIZE_MB must be between 1 and %d (local Bot API server limit), got %d", LocalAPIMaxDownloadMB, c.MaxFileSizeMB)
+	} else {
+		check(c.MaxFileSizeMB >= 1 && c.MaxFileSizeMB <= TelegramMaxDownloadMB,
+			"MAX_FILE_SIZE_MB must be between 1 and %d (Telegram bot download limit; set TELEGRAM_API_ENDPOINT to lift it), got %d",
+			TelegramMaxDownloadMB, c.MaxFileSizeMB)
+	}
+	check(c.MaxTotalSizeMB >= c.MaxFileSizeMB, "MAX_TOTAL_SIZE_MB must not be less than MAX_FILE_SIZE_MB (%d), got %d", c.MaxFileSizeMB, c.MaxTotalSizeMB)
+	check(c.SessionTimeoutMin >= 1 && c.SessionTimeoutMin <= 24*60, "SESSION_TIMEOUT_MINUTES must be between 1 and 1440, got %d", c.SessionTimeoutMin)
+	check(c.TempDir != "", "TEMP_DIR must not be empty")
+	check(c.DataDir != "", "DATA_DIR must not be empty")
+
+	check(c.MinMessages >= 1, "MIN_MESSAGES must be at least 1, got %d", c.MinMessages)
+	check(c.ListThreshold >= 1, "LIST_THRESHOLD must be at least 1, got %d", c.ListThreshold)
+
+	check(!c.PrivateChatsOnly || len(c.AllowedChats) == 0, "ALLOWED_CHATS must be empty when PRIVATE_CHATS_ONLY is enabled")
+
+	check(c.QuotaProcessPerHour >= 0, "QUOTA_PROCESS_PER_HOUR must not be negative, got %d", c.QuotaProcessPerHour)
+	check(c.QuotaProcessPerDay >= 0, "QUOTA_PROCESS_PER_DAY must not be negative, got %d", c.QuotaProcessPerDay)
+	check(c.QuotaUploadMBPerDay == 0 || c.QuotaUploadMBPerDay >= c.MaxFileSizeMB,
+		"QUOTA_UPLOAD_MB_PER_DAY must be 0 (unlimited) or not less than MAX_FILE_SIZE_MB (%d), got %d", c.MaxFileSizeMB, c.QuotaUploadMBPerDay)
+	check(c.QuotaCommandsPerMin >= 0, "QUOTA_COMMANDS_PER_MINUTE must not be negative, got %g", c.QuotaCommandsPerMin)
+	check(c.QuotaCommandBurst >= 1, "QUOTA_COMMAND_BURST must be at least 1, got %d", c.QuotaCommandBurst)
+	check(c.BanAfterViolations >= 0, "BAN_AFTER_VIOLATIONS must not be negative, got %d", c.BanAfterViolations)
+	if c.BanAfterViolations > 0 {
+		check(c.BanWindowMinutes >= 1, "BAN_WINDOW_MINUTES must be at least 1, got %d", c.BanWindowMinutes)
+		check(c.BanDurationMinutes >= 1, "BAN_DURATION_MINUTES must be at least 1, got %d", c.BanDurationMinutes)
+	}
+
+	check(c.SendGlobalRate > 0, "SEND_GLOBAL_RATE must be positive, got %g", c.SendGlobalRate)
+	check(c.SendChatRate > 0, "SEND_CHAT_RATE must be positive, got %g", c.SendChatRate)
+	check(c.SendChatBurst >= 1, "SEND_CHAT_BURST must be at least 1, got %d", c.SendChatBurst)
+	check(c.SendRetries >= 0 && c.SendRetries <= 10, "SEND_RETRIES must be between 0 and 10, got %d", c.SendRetries)
+
+	check(c.MinFreeDiskMB >= 0, "MIN_FREE_DISK_MB must not be negative, got %d", c.MinFreeDiskMB)
+	check(oneOf(c.TraceExporter, "none", "stdout", "otlp"), "TRACE_EXPORTER must be one of none, stdout, otlp, got %q", c.TraceExporter)
+	check(c.TraceSampleRatio >= 0 && c.TraceSampleRatio <= 1, "TRACE_SAMPLE_RATIO must be between 0 and 1, got %g", c.TraceSampleRatio)
+
+	return errors.Join(errs...)
+}
+
+// oneOf сообщает, что значение (без учёта регистра) входит в список допустимых
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if strings.EqualFold(value, a) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,367 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// value параметр конфигурации, который можно задать строкой из файла, окружения или флага
+type value interface {
+	Set(s string) error
+	String() string
+}
+
+// option описание параметра: имя переменной окружения, ключ файла и имя флага выводятся из env
+type option struct {
+	env    string
+	usage  string
+	secret bool
+	value  value
+}
+
+// fileKey ключ параметра в файле конфигурации: max_files
+func (o option) fileKey() string {
+	return strings.ToLower(o.env)
+}
+
+// flagName имя флага: --max-files
+func (o option) flagName() string {
+	return strings.ReplaceAll(strings.ToLower(o.env), "_", "-")
+}
+
+// options перечисляет параметры конфигурации в порядке вывода --print-config
+func (c *Config) options() []option {
+	return []option{
+		{env: "TELEGRAM_BOT_TOKEN", usage: "токен бота", secret: true, value: (*stringValue)(&c.Token)},
+		{env: "TELEGRAM_API_ENDPOINT", usage: "адрес собственного Bot API сервера; снимает лимит скачивания 20 МБ", value: (*stringValue)(&c.APIEndpoint)},
+
+		{env: "LOG_LEVEL", usage: "уровень логирования: debug, info, warn, error", value: (*stringValue)(&c.LogLevel)},
+		{env: "LOG_FORMAT", usage: "формат логов: text или json", value: (*stringValue)(&c.LogFormat)},
+		{env: "LOG_OUTPUT", usage: "приёмник логов: stderr, stdout или путь к файлу", value: (*stringValue)(&c.LogOutput)},
+		{env: "LOG_SOURCE", usage: "добавлять в логи файл и строку вызова", value: (*boolValue)(&c.LogSource)},
+		{env: "LOG_HASH_KEY", usage: "ключ HMAC для персональных данных в логах", secret: true, value: (*stringValue)(&c.LogHashKey)},
+
+		{env: "MAX_FILES", usage: "максимум файлов в сессии", value: (*intValue)(&c.MaxFiles)},
+		{env: "MAX_FILE_SIZE_MB", usage: "максимальный размер одного файла, МБ", value: (*intValue)(&c.MaxFileSizeMB)},
+		{env: "MAX_TOTAL_SIZE_MB", usage: "максимальный размер файлов в сессии, МБ", value: (*intValue)(&c.MaxTotalSizeMB)},
+		{env: "SESSION_TIMEOUT_MINUTES", usage: "время жизни сессии, минуты", value: (*intValue)(&c.SessionTimeoutMin)},
+		{env: "TEMP_DIR", usage: "директория временных файлов", value: (*stringValue)(&c.TempDir)},
+		{env: "TEMP_SECURE_DELETE", usage: "перезаписывать временные файлы нулями перед удалением", value: (*boolValue)(&c.TempSecureDelete)},
+		{env: "DATA_DIR", usage: "директория постоянных данных (списки игнорирования)", value: (*stringValue)(&c.DataDir)},
+
+		{env: "MIN_MESSAGES", usage: "минимум сообщений от автора", value: (*intValue)(&c.MinMessages)},
+		{env: "INCLUDE_MENTIONS", usage: "собирать упоминания", value: (*boolValue)(&c.IncludeMentions)},
+		{env: "CASE_SENSITIVE", usage: "различать регистр имён", value: (*boolValue)(&c.CaseSensitive)},
+		{env: "EXCLUDE_USERS", usage: "исключаемые пользователи через запятую", value: (*listValue)(&c.ExcludeUsers)},
+		{env: "LIST_THRESHOLD", usage: "количество участников, начиная с которого результат отправляется в Excel", value: (*intValue)(&c.ListThreshold)},
+		{env: "CONTACTS_EXTRACTION_ENABLED", usage: "разрешить извлечение телефонов и email", value: (*boolValue)(&c.ContactsEnabled)},
+
+		{env: "ALLOWED_USERS", usage: "пользователи (ID или username через запятую), которым доступен бот; пусто — всем", value: (*listValue)(&c.AllowedUsers)},
+		{env: "ALLOWED_CHATS", usage: "ID групповых чатов через запятую, в которых работает бот; пусто — любые", value: (*int64ListValue)(&c.AllowedChats)},
+		{env: "ADMIN_USERS", usage: "числовые ID администраторов бота через запятую", value: (*int64ListValue)(&c.AdminUsers)},
+		{env: "PRIVATE_CHATS_ONLY", usage: "отвечать только в личных чатах", value: (*boolValue)(&c.PrivateChatsOnly)},
+
+		{env: "QUOTA_PROCESS_PER_HOUR", usage: "обработок /process на пользователя в час; 0 — без лимита", value: (*intValue)(&c.QuotaProcessPerHour)},
+		{env: "QUOTA_PROCESS_PER_DAY", usage: "обработок /process на пользователя в сутки; 0 — без лимита", value: (*intValue)(&c.QuotaProcessPerDay)},
+		{env: "QUOTA_UPLOAD_MB_PER_DAY", usage: "объём загрузок на пользователя в сутки, МБ; 0 — без лимита", value: (*intValue)(&c.QuotaUploadMBPerDay)},
+		{env: "QUOTA_COMMANDS_PER_MINUTE", usage: "команд и файлов от пользователя в минуту; 0 — без лимита", value: (*floatValue)(&c.QuotaCommandsPerMin)},
+		{env: "QUOTA_COMMAND_BURST", usage: "команд подряд без паузы", value: (*intValue)(&c.QuotaCommandBurst)},
+		{env: "BAN_AFTER_VIOLATIONS", usage: "нарушений лимитов до временной блокировки; 0 — без блокировок", value: (*intValue)(&c.BanAfterViolations)},
+		{env: "BAN_WINDOW_MINUTES", usage: "окно подсчёта нарушений, минуты", value: (*intValue)(&c.BanWindowMinutes)},
+		{env: "BAN_DURATION_MINUTES", usage: "длительность временной блокировки, минуты", value: (*intValue)(&c.BanDurationMinutes)},
+
+		{env: "SEND_GLOBAL_RATE", usage: "исходящих сообщений бота в секунду", value: (*floatValue)(&c.SendGlobalRate)},
+		{env: "SEND_CHAT_RATE", usage: "исходящих сообщений в один чат в секунду", value: (*floatValue)(&c.SendChatRate)},
+		{env: "SEND_CHAT_BURST", usage: "сообщений в чат без паузы", value: (*intValue)(&c.SendChatBurst)},
+		{env: "SEND_RETRIES", usage: "повторов при 429 и сетевых ошибках", value: (*intValue)(&c.SendRetries)},
+
+		{env: "ADMIN_ADDR", usage: "адрес служебного HTTP сервера (/metrics, /healthz, /readyz)", value: (*stringValue)(&c.AdminAddr)},
+		{env: "MIN_FREE_DISK_MB", usage: "минимум свободного места в TEMP_DIR для /readyz, МБ", value: (*intValue)(&c.MinFreeDiskMB)},
+
+		{env: "TRACE_EXPORTER", usage: "экспорт спанов: none, stdout, otlp", value: (*stringValue)(&c.TraceExporter)},
+		{env: "TRACE_ENDPOINT", usage: "адрес OTLP/HTTP коллектора", value: (*stringValue)(&c.TraceEndpoint)},
+		{env: "TRACE_SAMPLE_RATIO", usage: "доля записываемых трасс (0..1, 0 — не записывать)", value: (*floatValue)(&c.TraceSampleRatio)},
+	}
+}
+
+// Flags результат разбора служебных флагов командной строки
+type Flags struct {
+	// PrintConfig вывести итоговую конфигурацию и завершиться
+	PrintConfig bool
+}
+
+// Load собирает конфигурацию из значений по умолчанию, файла, окружения и флагов args.
+// lookupEnv ведёт себя как os.LookupEnv: заданная, но пустая переменная окружения
+// возвращает параметр к значению по умолчанию, отменяя значение из файла.
+// Ошибки разбора и проверки возвращаются все сразу; конфигурация возвращается и при ошибке,
+// чтобы её можно было вывести через --print-config.
+func Load(args []string, lookupEnv func(string) (string, bool)) (Config, Flags, error) {
+	cfg := Default()
+	opts := cfg.options()
+
+	// Флаги разбираем первыми, но применяем последними: они нужны, чтобы найти файл конфигурации
+	var flags Flags
+	var configFile string
+	fromFlags := make(map[string]string)
+
+	fs := flag.NewFlagSet("bot", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.StringVar(&configFile, "config", "", "путь к YAML или TOML файлу конфигурации (или CONFIG_FILE)")
+	fs.BoolVar(&flags.PrintConfig, "print-config", false, "вывести итоговую конфигурацию со скрытыми секретами и завершиться")
+	for _, o := range opts {
+		fs.Var(&recordedFlag{env: o.env, isBool: isBool(o.value), values: fromFlags}, o.flagName(), o.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, flags, fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	var errs []error
+	set := func(o option, source, raw string) {
+		if err := o.value.Set(raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s from %s: invalid value %q: %w", o.env, source, raw, err))
+		}
+	}
+
+	if configFile == "" {
+		configFile, _ = lookupEnv("CONFIG_FILE")
+	}
+	if configFile != "" {
+		fileValues, err := readFile(configFile)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		known := make(map[string]bool, len(opts))
+		for _, o := range opts {
+			known[o.fileKey()] = true
+			if raw, ok := fileValues[o.fileKey()]; ok {
+				set(o, configFile, raw)
+			}
+		}
+		for _, key := range sortedKeys(fileValues) {
+			if !known[key] {
+				errs = append(errs, fmt.Errorf("%s: unknown key %q", configFile, key))
+			}
+		}
+	}
+
+	defaults := Default()
+	defaultOpts := defaults.options()
+	for i, o := range opts {
+		raw, ok := lookupEnv(o.env)
+		if !ok {
+			continue
+		}
+		if strings.TrimSpace(raw) == "" {
+			raw = defaultOpts[i].value.String()
+		}
+		set(o, "environment", raw)
+	}
+
+	for _, o := range opts {
+		if raw, ok := fromFlags[o.env]; ok {
+			set(o, "flag --"+o.flagName(), raw)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	return cfg, flags, errors.Join(errs...)
+}
+
+// Print выводит итоговую конфигурацию в формате файла конфигурации, скрывая секреты
+func (c Config) Print(w io.Writer) error {
+	for _, o := range c.options() {
+		v := o.value.String()
+		if o.secret && v != "" {
+			v = "********"
+		}
+		switch o.value.(type) {
+		case *stringValue, *listValue, *int64ListValue:
+			v = strconv.Quote(v)
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\n", o.fileKey(), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFile читает YAML или TOML файл конфигурации в плоский набор строковых значений.
+// Формат определяется по расширению: .yaml, .yml или .toml.
+func readFile(path string) (map[string]string, error) {
+	var unmarshal func([]byte, any) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		unmarshal = yaml.Unmarshal
+	case ".toml":
+		unmarshal = toml.Unmarshal
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q: expected .yaml, .yml or .toml", filepath.Ext(path))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, v := range raw {
+		switch v := v.(type) {
+		case nil:
+			values[strings.ToLower(key)] = ""
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			values[strings.ToLower(key)] = strings.Join(items, ",")
+		default:
+			values[strings.ToLower(key)] = fmt.Sprint(v)
+		}
+	}
+	return values, nil
+}
+
+// sortedKeys возвращает ключи в стабильном порядке для сообщений об ошибках
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// recordedFlag запоминает значение флага, чтобы применить его после файла и окружения
+type recordedFlag struct {
+	env    string
+	isBool bool
+	values map[string]string
+}
+
+func (f *recordedFlag) Set(s string) error {
+	f.values[f.env] = s
+	return nil
+}
+
+func (f *recordedFlag) String() string {
+	if f == nil || f.values == nil {
+		return ""
+	}
+	return f.values[f.env]
+}
+
+// IsBoolFlag позволяет писать --contacts-extraction-enabled без значения
+func (f *recordedFlag) IsBoolFlag() bool {
+	return f.isBool
+}
+
+func isBool(v value) bool {
+	_, ok := v.(*boolValue)
+	return ok
+}
+
+type stringValue string
+
+func (v *stringValue) Set(s string) error {
+	*v = stringValue(strings.TrimSpace(s))
+	return nil
+}
+
+func (v *stringValue) String() string { return string(*v) }
+
+type intValue int
+
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return errors.New("not an integer")
+	}
+	*v = intValue(n)
+	return nil
+}
+
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+
+type floatValue float64
+
+func (v *floatValue) Set(s string) error {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return errors.New("not a number")
+	}
+	*v = floatValue(f)
+	return nil
+}
+
+func (v *floatValue) String() string { return strconv.FormatFloat(float64(*v), 'g', -1, 64) }
+
+type boolValue bool
+
+func (v *boolValue) Set(s string) error {
+	b, err := strconv.ParseBool(strings.TrimSpace(s))
+	if err != nil {
+		return errors.New("not a boolean")
+	}
+	*v = boolValue(b)
+	return nil
+}
+
+func (v *boolValue) String() string { return strconv.FormatBool(bool(*v)) }
+
+type listValue []string
+
+func (v *listValue) Set(s string) error {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*v = items
+	return nil
+}
+
+func (v *listValue) String() string { return strings.Join(*v, ",") }
+
+type int64ListValue []int64
+
+func (v *int64ListValue) Set(s string) error {
+	var ids []int64
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(item, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer ID", item)
+		}
+		ids = append(ids, id)
+	}
+	*v = ids
+	return nil
+}
+
+func (v *int64ListValue) String() string {
+	items := make([]string, len(*v))
+	for i, id := range *v {
+		items[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(items, ",")
+}
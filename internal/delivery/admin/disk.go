@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+)
+
+// FreeBytes возвращает свободное место на диске с каталогом; на платформах без Statfs — ошибку
+func FreeBytes(dir string) (uint64, error) {
+	return freeBytes(dir)
+}
+
+// DirSize возвращает суммарный размер файлов в каталоге и его подкаталогах.
+// Файлы, удалённые во время обхода, пропускаются.
+func DirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package admin
+
+// freeBytes на остальных платформах не поддерживается, проверка свободного места пропускается
+func freeBytes(string) (uint64, error) {
+	return 0, errFreeSpaceUnsupported
+}
@@ -0,0 +1,14 @@
+//go:build linux || darwin
+
+package admin
+
+import "syscall"
+
+// freeBytes возвращает место на диске, доступное непривилегированному процессу
+func freeBytes(dir string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, err
+	}
+	return st.Bavail * uint64(st.Bsize), nil
+}
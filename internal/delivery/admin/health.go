@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+)
+
+// checkTimeout ограничивает время одной проверки состояния
+const checkTimeout = 5 * time.Second
+
+// errFreeSpaceUnsupported платформа не позволяет узнать свободное место на диске
+var errFreeSpaceUnsupported = errors.New("free disk space check is not supported on this platform")
+
+// Check проверка состояния; nil означает, что компонент исправен
+type Check func(ctx context.Context) error
+
+// healthResponse ответ /healthz и /readyz
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// HealthHandler выполняет проверки и отвечает 200, если все прошли, иначе 503.
+// Причины ошибок попадают в ответ: эндпоинт служебный и не раскрывает данных пользователей.
+func HealthHandler(checks map[string]Check) http.Handler {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+
+		resp := healthResponse{Status: "ok", Checks: make(map[string]string, len(checks))}
+		for _, name := range names {
+			if err := checks[name](ctx); err != nil {
+				resp.Status = "fail"
+				resp.Checks[name] = err.Error()
+				continue
+			}
+			resp.Checks[name] = "ok"
+		}
+
+		code := http.StatusOK
+		if resp.Status != "ok" {
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// WritableDir проверяет, что в каталог можно записать файл
+func WritableDir(dir string) Check {
+	return func(context.Context) error {
+		f, err := os.CreateTemp(dir, ".healthcheck-*")
+		if err != nil {
+			return fmt.Errorf("directory is not writable: %w", err)
+		}
+		name := f.Name()
+		if err := f.Close(); err != nil {
+			_ = os.Remove(name)
+			return fmt.Errorf("failed to close probe file: %w", err)
+		}
+		if err := os.Remove(name); err != nil {
+			return fmt.Errorf("failed to remove probe file: %w", err)
+		}
+		return nil
+	}
+}
+
+// FreeSpace проверяет, что на диске с каталогом свободно не меньше minBytes
+func FreeSpace(dir string, minBytes uint64) Check {
+	return func(context.Context) error {
+		free, err := freeBytes(dir)
+		if errors.Is(err, errFreeSpaceUnsupported) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get free disk space: %w", err)
+		}
+		if free < minBytes {
+			return fmt.Errorf("free disk space %d MB is below %d MB", free>>20, minBytes>>20)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/pkg/logger"
+)
+
+// readHeaderTimeout ограничивает время чтения заголовков запроса
+const readHeaderTimeout = 5 * time.Second
+
+// Server служебный HTTP сервер бота (метрики, проверки состояния).
+// Слушает отдельный адрес и не должен быть доступен из интернета.
+type Server struct {
+	srv    *http.Server
+	mux    *http.ServeMux
+	logger *logger.Logger
+}
+
+// New создаёт служебный сервер на адресе addr (например, ":9090")
+func New(addr string, log *logger.Logger) *Server {
+	mux := http.NewServeMux()
+	return &Server{
+		srv: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: readHeaderTimeout,
+		},
+		mux:    mux,
+		logger: log,
+	}
+}
+
+// Handle регистрирует обработчик для пути
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start запускает сервер в отдельной горутине
+func (s *Server) Start() {
+	go func() {
+		s.logger.Info("admin server started", "addr", s.srv.Addr)
+		if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("admin server stopped", "error", err)
+		}
+	}()
+}
+
+// Shutdown останавливает сервер, дожидаясь завершения активных запросов
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
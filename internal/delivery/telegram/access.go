@@ -0,0 +1,133 @@
+package telegram
+
+import (
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Причины отказа в доступе
+const (
+	DenyUser        = "user"
+	DenyChat        = "chat"
+	DenyPrivateOnly = "private_only"
+	DenyAnonymous   = "anonymous"
+)
+
+// AccessConfig политика доступа к боту. Разрешённые пользователи задаются числовым ID или username
+// (с @ или без); username может смениться, поэтому для постоянного доступа надёжнее ID.
+type AccessConfig struct {
+	// AllowedUsers пользователи, которым разрешён бот; пустой список — всем
+	AllowedUsers []string
+	// AllowedChats групповые чаты, в которых работает бот; пустой список — любые группы
+	AllowedChats []int64
+	// Admins числовые ID администраторов бота; им доступ разрешён всегда. Username не принимаются:
+	// освободившийся username может занять другой человек и получить права администратора.
+	Admins []int64
+	// PrivateOnly бот отвечает только в личных чатах
+	PrivateOnly bool
+}
+
+// accessPolicy проверяет доступ по AccessConfig
+type accessPolicy struct {
+	users       userSet
+	admins      map[int64]bool
+	chats       map[int64]bool
+	privateOnly bool
+}
+
+func newAccessPolicy(cfg AccessConfig) *accessPolicy {
+	p := &accessPolicy{
+		users:       newUserSet(cfg.AllowedUsers),
+		admins:      make(map[int64]bool, len(cfg.Admins)),
+		chats:       make(map[int64]bool, len(cfg.AllowedChats)),
+		privateOnly: cfg.PrivateOnly,
+	}
+	for _, id := range cfg.Admins {
+		p.admins[id] = true
+	}
+	for _, id := range cfg.AllowedChats {
+		p.chats[id] = true
+	}
+	return p
+}
+
+// check возвращает причину отказа или пустую строку, если доступ разрешён.
+// Ограничения чатов действуют и для администраторов, список пользователей — нет.
+func (p *accessPolicy) check(from *tgbotapi.User, chat *tgbotapi.Chat) string {
+	if from == nil || chat == nil {
+		return DenyAnonymous
+	}
+
+	if !chat.IsPrivate() {
+		if p.privateOnly {
+			return DenyPrivateOnly
+		}
+		if len(p.chats) > 0 && !p.chats[chat.ID] {
+			return DenyChat
+		}
+	}
+
+	if len(p.users) > 0 && !p.users.contains(from) && !p.admins[from.ID] {
+		return DenyUser
+	}
+	return ""
+}
+
+// isAdmin сообщает, что пользователь входит в список администраторов
+func (p *accessPolicy) isAdmin(from *tgbotapi.User) bool {
+	return from != nil && p.admins[from.ID]
+}
+
+// isAdminID сообщает, что пользователь с этим ID — администратор
+func (p *accessPolicy) isAdminID(userID int64) bool {
+	return p.admins[userID]
+}
+
+// userSet множество пользователей по ID и username
+type userSet map[string]bool
+
+func newUserSet(entries []string) userSet {
+	set := make(userSet, len(entries))
+	for _, e := range entries {
+		if key := normalizeUserKey(e); key != "" {
+			set[key] = true
+		}
+	}
+	return set
+}
+
+func (s userSet) contains(u *tgbotapi.User) bool {
+	if s[strconv.FormatInt(u.ID, 10)] {
+		return true
+	}
+	return u.UserName != "" && s[normalizeUserKey(u.UserName)]
+}
+
+// normalizeUserKey приводит запись к виду ключа: ID как есть, username без @ в нижнем регистре
+func normalizeUserKey(entry string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(entry), "@"))
+}
+
+// denyAccess сообщает пользователю об отказе. В группах бот отвечает только на команды,
+// чтобы не писать на каждое сообщение участников.
+func (b *Bot) denyAccess(msg *tgbotapi.Message, reason string) {
+	b.metrics.AccessDenied(reason)
+	if reason == DenyAnonymous || (!msg.Chat.IsPrivate() && !msg.IsCommand()) {
+		return
+	}
+
+	// Настройки отклонённого пользователя не сохраняются, поэтому язык берётся из профиля
+	lang := DetectLang(msg.From.LanguageCode)
+	var text string
+	switch reason {
+	case DenyPrivateOnly:
+		text = formatHTML(translate(lang, MessageAccessPrivateOnly))
+	case DenyChat:
+		text = formatHTML(translate(lang, MessageAccessChatDenied), msg.Chat.ID)
+	default:
+		text = formatHTML(translate(lang, MessageAccessDenied), msg.From.ID)
+	}
+	b.sendMessage(msg.Chat.ID, text)
+}
@@ -0,0 +1,252 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/delivery/admin"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/session"
+	"github.com/MaxFando/tg-export-chat-analyzer/pkg/logger"
+)
+
+// adminSessionsLimit сколько сессий показывает /admin_sessions
+const adminSessionsLimit = 30
+
+// isAdminCommand сообщает, что команда относится к администрированию: /admin и /admin_*
+func isAdminCommand(command string) bool {
+	return command == "admin" || strings.HasPrefix(command, "admin_")
+}
+
+// handleAdminCommand выполняет команду администратора. Остальным пользователям команды
+// не видны: они получают ответ как на неизвестную команду.
+func (b *Bot) handleAdminCommand(log *logger.Logger, msg *tgbotapi.Message) {
+	userID := msg.From.ID
+	chatID := msg.Chat.ID
+	command := msg.Command()
+	args := strings.TrimSpace(msg.CommandArguments())
+
+	if !b.access.isAdmin(msg.From) {
+		log.Warn("admin command from non-admin user", "command", command)
+		b.metrics.CommandReceived("unknown")
+		b.sendMessage(chatID, b.text(userID, MessageUnknownCommand))
+		return
+	}
+
+	switch command {
+	case "admin":
+		b.sendMessage(chatID, b.text(userID, MessageAdminHelp))
+	case "admin_stats":
+		b.cmdAdminStats(userID, chatID)
+	case "admin_sessions":
+		b.cmdAdminSessions(userID, chatID)
+	case "admin_kill":
+		b.cmdAdminKill(log, userID, chatID, args)
+	case "admin_broadcast":
+		b.cmdAdminBroadcast(log, userID, chatID, args)
+	case "admin_loglevel":
+		b.cmdAdminLogLevel(log, userID, chatID, args)
+	case "admin_bans":
+		b.cmdAdminBans(userID, chatID)
+	case "admin_unban":
+		b.cmdAdminUnban(log, userID, chatID, args)
+	default:
+		command = "unknown"
+		b.sendMessage(chatID, b.text(userID, MessageAdminHelp))
+	}
+
+	b.metrics.CommandReceived(command)
+}
+
+// cmdAdminStats показывает состояние бота: сессии, очереди, диск TEMP_DIR и время работы
+func (b *Bot) cmdAdminStats(userID, chatID int64) {
+	sessions := b.sessionManager.List()
+	files := 0
+	for _, s := range sessions {
+		files += len(s.Files)
+	}
+
+	used := b.text(userID, MessageAdminUnknown)
+	if size, err := admin.DirSize(b.tempDir); err == nil {
+		used = formatMB(uint64(size))
+	} else {
+		b.logger.Warn("failed to measure temp dir", "error", err)
+	}
+	free := b.text(userID, MessageAdminUnknown)
+	if n, err := admin.FreeBytes(b.tempDir); err == nil {
+		free = formatMB(n)
+	}
+
+	b.sendMessage(chatID, b.text(userID, MessageAdminStats,
+		formatDuration(time.Since(b.started)),
+		len(sessions), files,
+		len(b.quota.Bans()),
+		b.inFlight.Load(),
+		b.sender.Pending(),
+		used, free,
+		string(b.logger.Level())))
+}
+
+// cmdAdminSessions перечисляет сессии, начиная с последней активной
+func (b *Bot) cmdAdminSessions(userID, chatID int64) {
+	sessions := b.sessionManager.List()
+	if len(sessions) == 0 {
+		b.sendMessage(chatID, b.text(userID, MessageAdminNoSessions))
+		return
+	}
+
+	shown := sessions
+	if len(shown) > adminSessionsLimit {
+		shown = shown[:adminSessionsLimit]
+	}
+
+	now := time.Now()
+	lines := make([]string, 0, len(shown)+1)
+	for _, s := range shown {
+		lines = append(lines, b.text(userID, MessageAdminSessionLine,
+			s.ID, s.UserID, string(s.State), len(s.Files),
+			formatDuration(now.Sub(s.CreatedAt)), formatDuration(now.Sub(s.UpdatedAt))))
+	}
+	if rest := len(sessions) - len(shown); rest > 0 {
+		lines = append(lines, b.text(userID, MessageAdminSessionsMore, rest))
+	}
+
+	b.sendMessage(chatID, b.text(userID, MessageAdminSessions, len(sessions), safeHTML(strings.Join(lines, "\n"))))
+}
+
+// cmdAdminKill сбрасывает сессию пользователя и удаляет её файлы.
+// Сессия задаётся ID пользователя или ID сессии из /admin_sessions.
+func (b *Bot) cmdAdminKill(log *logger.Logger, userID, chatID int64, args string) {
+	if args == "" {
+		b.sendMessage(chatID, b.text(userID, MessageAdminKillUsage))
+		return
+	}
+
+	target, ok := b.findSession(args)
+	if !ok {
+		b.sendMessage(chatID, b.text(userID, MessageAdminKillNotFound, args))
+		return
+	}
+
+	b.deleteSessionFiles(target.ID, target.Files)
+	b.sessionManager.Clear(target.UserID)
+	log.Warn("session killed by admin", "session_id", target.ID, "files", len(target.Files))
+
+	b.sendMessage(chatID, b.text(userID, MessageAdminKilled, target.ID, len(target.Files)))
+	// Настройки удалены вместе с сессией, поэтому язык берётся из сохранённой копии
+	lang, ok := ParseLang(target.Settings.Language)
+	if !ok {
+		lang = DetectLang(target.Settings.ClientLanguage)
+	}
+	b.sendMessage(target.UserID, formatHTML(translate(lang, MessageAdminSessionReset)))
+}
+
+// findSession ищет сессию по ID сессии или ID пользователя
+func (b *Bot) findSession(key string) (session.Session, bool) {
+	for _, s := range b.sessionManager.List() {
+		if s.ID == key || strconv.FormatInt(s.UserID, 10) == key {
+			return s, true
+		}
+	}
+	return session.Session{}, false
+}
+
+// cmdAdminBroadcast отправляет уведомление всем пользователям с активными сессиями
+func (b *Bot) cmdAdminBroadcast(log *logger.Logger, userID, chatID int64, text string) {
+	if text == "" {
+		b.sendMessage(chatID, b.text(userID, MessageAdminBroadcastUsage))
+		return
+	}
+
+	sessions := b.sessionManager.List()
+	sent := 0
+	for _, s := range sessions {
+		// Сессии создаются в личном чате, поэтому ID пользователя совпадает с ID чата
+		msg := tgbotapi.NewMessage(s.UserID, b.text(s.UserID, MessageAdminBroadcast, text))
+		msg.ParseMode = parseModeHTML
+		if _, err := b.sender.Send(s.UserID, msg); err != nil {
+			log.Warn("failed to deliver broadcast", "session_id", s.ID, "error", err)
+			continue
+		}
+		sent++
+	}
+
+	log.Info("broadcast sent", "sent", sent, "total", len(sessions))
+	b.sendMessage(chatID, b.text(userID, MessageAdminBroadcastDone, sent, len(sessions)))
+}
+
+// cmdAdminLogLevel показывает или меняет уровень логирования во время работы
+func (b *Bot) cmdAdminLogLevel(log *logger.Logger, userID, chatID int64, level string) {
+	if level == "" {
+		b.sendMessage(chatID, b.text(userID, MessageAdminLogLevelUsage, string(b.logger.Level())))
+		return
+	}
+
+	previous := b.logger.Level()
+	if err := b.logger.SetLevel(level); err != nil {
+		b.sendMessage(chatID, b.text(userID, MessageAdminLogLevelUsage, string(previous)))
+		return
+	}
+
+	log.Warn("log level changed by admin", "from", string(previous), "to", string(b.logger.Level()))
+	b.sendMessage(chatID, b.text(userID, MessageAdminLogLevelSet, string(b.logger.Level())))
+}
+
+// cmdAdminBans перечисляет действующие временные блокировки
+func (b *Bot) cmdAdminBans(userID, chatID int64) {
+	bans := b.quota.Bans()
+	if len(bans) == 0 {
+		b.sendMessage(chatID, b.text(userID, MessageAdminNoBans))
+		return
+	}
+
+	now := time.Now()
+	lines := make([]string, 0, len(bans))
+	for _, ban := range bans {
+		lines = append(lines, b.text(userID, MessageAdminBanLine,
+			ban.UserID, ban.Until.UTC().Format("2006-01-02 15:04 UTC"), formatRetry(ban.Until.Sub(now))))
+	}
+	b.sendMessage(chatID, b.text(userID, MessageAdminBans, len(bans), safeHTML(strings.Join(lines, "\n"))))
+}
+
+// cmdAdminUnban снимает временную блокировку пользователя
+func (b *Bot) cmdAdminUnban(log *logger.Logger, userID, chatID int64, args string) {
+	if args == "" {
+		b.sendMessage(chatID, b.text(userID, MessageAdminUnbanUsage))
+		return
+	}
+
+	target, err := strconv.ParseInt(args, 10, 64)
+	if err != nil {
+		b.sendMessage(chatID, b.text(userID, MessageAdminUnbanUsage))
+		return
+	}
+
+	unbanned, err := b.quota.Unban(target)
+	if err != nil {
+		log.Error("failed to save quotas", "error", err)
+	}
+	if !unbanned {
+		b.sendMessage(chatID, b.text(userID, MessageAdminUnbanNotFound, args))
+		return
+	}
+
+	log.Warn("user unbanned by admin")
+	b.sendMessage(chatID, b.text(userID, MessageAdminUnbanned, target))
+}
+
+// formatMB выводит размер в мегабайтах
+func formatMB(bytes uint64) string {
+	return fmt.Sprintf("%.1f MB", float64(bytes)/(1024*1024))
+}
+
+// formatDuration округляет длительность до секунд, а после часа — до минут
+func formatDuration(d time.Duration) string {
+	if d >= time.Hour {
+		return d.Round(time.Minute).String()
+	}
+	return d.Round(time.Second).String()
+}
@@ -1,32 +1,90 @@
 package telegram
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/delivery/admin"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/charts"
 	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/export"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/filter"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/ignorelist"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/interaction"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/metadata"
 	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/participant"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/quota"
 	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/session"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/stats"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/inqast/fstorage/storage"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/MaxFando/tg-export-chat-analyzer/pkg/cryptostorage"
 	"github.com/MaxFando/tg-export-chat-analyzer/pkg/logger"
+	"github.com/MaxFando/tg-export-chat-analyzer/pkg/metrics"
 
 	"github.com/Nikalively/telegram-export-parser/parser"
 	"github.com/lintenved/tg-exporter/exporter"
 )
 
+// topInteractionsLimit количество строк в текстовой сводке взаимодействий
+const topInteractionsLimit = 5
+
+// shutdownTimeout сколько Start ждёт завершения уже полученных обновлений после остановки.
+// Вместе со сбросом трассировки должно укладываться в паузу между SIGTERM и SIGKILL (10 секунд у docker stop).
+const shutdownTimeout = 6 * time.Second
+
+// tracer создаёт спаны этапов обработки
+var tracer = otel.Tracer("github.com/MaxFando/tg-export-chat-analyzer/internal/delivery/telegram")
+
+// extractorDefaults настройки извлечения участников из конфигурации бота
+type extractorDefaults struct {
+	MinMessages     int
+	IncludeMentions bool
+	ExcludeUsers    []string
+	CaseSensitive   bool
+}
+
 // Bot управляет Telegram ботом
 type Bot struct {
 	api               *tgbotapi.BotAPI
+	fileEndpoint      string
+	localAPI          bool
+	sender            *Sender
 	sessionManager    *session.Manager
-	tempStorage       storage.TempStorage
-	participantSvc    *participant.ParticipantExtractor
+	tempStorage       *cryptostorage.Storage
+	interactionSvc    *interaction.Analyzer
 	exportSvc         *export.Service
+	ignoreStore       *ignorelist.Store
+	defaults          extractorDefaults
+	contactsEnabled   bool
+	access            *accessPolicy
+	quota             *quota.Tracker
+	uploadQuotaMB     int64
 	logger            *logger.Logger
+	metrics           *metrics.Metrics
+	admin             *admin.Server
+	polling           atomic.Bool
+	lastGetMe         atomic.Int64
+	inFlight          atomic.Int64
+	handlers          sync.WaitGroup
+	started           time.Time
+	tempDir           string
 	maxFiles          int
 	maxFileSizeMB     int
 	maxTotalSizeMB    int
@@ -35,97 +93,309 @@ type Bot struct {
 
 // Config конфигурация для бота
 type Config struct {
-	Token             string
+	Token string
+	// APIEndpoint адрес собственного Bot API сервера (telegram-bot-api --local), например
+	// http://telegram-bot-api:8081; пустая строка — облачный api.telegram.org с лимитом скачивания 20 МБ
+	APIEndpoint       string
 	MaxFiles          int
 	MaxFileSizeMB     int
 	MaxTotalSizeMB    int
 	SessionTimeoutMin int
 	LogLevel          string
+	LogFormat         string
+	LogOutput         string
+	LogSource         bool
+	LogHashKey        string
 	TempDir           string
+	DataDir           string
+
+	// SecureDelete перезаписывает временные файлы нулями перед удалением
+	SecureDelete bool
+
+	// Настройки извлечения участников по умолчанию
+	MinMessages     int
+	IncludeMentions bool
+	ExcludeUsers    []string
+	CaseSensitive   bool
+	ListThreshold   int
+
+	// ContactsEnabled разрешает пользователям включать извлечение телефонов и email
+	ContactsEnabled bool
+
+	// Access политика доступа: разрешённые пользователи и чаты, администраторы
+	Access AccessConfig
+
+	// Quota лимиты пользователя: обработки, объём загрузок, частота команд и временные блокировки
+	Quota quota.Limits
+
+	// Лимиты исходящих сообщений
+	Sender SenderConfig
+
+	// AdminAddr адрес служебного HTTP сервера с /metrics, /healthz и /readyz; пустая строка — сервер выключен
+	AdminAddr string
+	// MinFreeDiskMB минимум свободного места в TempDir для /readyz
+	MinFreeDiskMB int
 }
 
 // New создаёт новый бот
 func New(cfg Config) (*Bot, error) {
-	// Инициализируем API Telegram
-	api, err := tgbotapi.NewBotAPI(cfg.Token)
+	// Инициализируем API Telegram: облачный или собственный сервер
+	apiEndpoint, fileEndpoint := tgbotapi.APIEndpoint, tgbotapi.FileEndpoint
+	localAPI := cfg.APIEndpoint != ""
+	if localAPI {
+		base := strings.TrimRight(cfg.APIEndpoint, "/")
+		apiEndpoint, fileEndpoint = base+"/bot%s/%s", base+"/file/bot%s/%s"
+	}
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint(cfg.Token, apiEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bot API: %w", err)
 	}
 
 	// Инициализируем логгер
-	log := logger.New(cfg.LogLevel)
-
-	// Инициализируем временное хранилище
-	tmpStorage, err := storage.NewFileSystemStorage(cfg.TempDir)
+	logOutput, err := logger.OpenOutput(cfg.LogOutput)
+	if err != nil {
+		return nil, err
+	}
+	log := logger.New(cfg.LogLevel,
+		logger.WithFormat(cfg.LogFormat),
+		logger.WithOutput(logOutput),
+		logger.WithSource(cfg.LogSource),
+		logger.WithHashKey([]byte(cfg.LogHashKey)))
+
+	// Инициализируем временное хранилище: файлы шифруются ключом сессии
+	fsStorage, err := storage.NewFileSystemStorage(cfg.TempDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temp storage: %w", err)
 	}
+	tmpStorage := cryptostorage.New(fsStorage, cryptostorage.WithSecureDelete(cfg.SecureDelete))
+
+	// Инициализируем хранилище списков игнорирования
+	ignoreStore, err := ignorelist.NewStore(filepath.Join(cfg.DataDir, "ignorelists"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ignore list store: %w", err)
+	}
+
+	// Счётчики квот хранятся рядом со списками игнорирования, чтобы переживать перезапуск
+	quotaTracker, err := quota.NewTracker(filepath.Join(cfg.DataDir, "quotas.json"), cfg.Quota)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quota tracker: %w", err)
+	}
 
 	// Создаём сессионный менеджер
 	sessionMgr := session.NewManager(time.Duration(cfg.SessionTimeoutMin) * time.Minute)
 
 	// Создаём сервисы
-	partSvc := participant.New()
-	expSvc := export.New()
+	interSvc := interaction.New()
+	expSvc := export.New(export.WithListThreshold(cfg.ListThreshold))
+	defaults := extractorDefaults{
+		MinMessages:     cfg.MinMessages,
+		IncludeMentions: cfg.IncludeMentions,
+		ExcludeUsers:    cfg.ExcludeUsers,
+		CaseSensitive:   cfg.CaseSensitive,
+	}
+
+	// Служебный сервер с метриками включается только при заданном адресе
+	var m *metrics.Metrics
+	var adminSrv *admin.Server
+	if cfg.AdminAddr != "" {
+		m = metrics.New()
+		m.RegisterActiveSessions(sessionMgr.Count)
+
+		adminSrv = admin.New(cfg.AdminAddr, log)
+		adminSrv.Handle("/metrics", m.Handler())
+	}
 
 	bot := &Bot{
 		api:               api,
+		fileEndpoint:      fileEndpoint,
+		localAPI:          localAPI,
+		sender:            NewSender(api, log, m, cfg.Sender),
 		sessionManager:    sessionMgr,
 		tempStorage:       tmpStorage,
-		participantSvc:    partSvc,
+		interactionSvc:    interSvc,
 		exportSvc:         expSvc,
+		ignoreStore:       ignoreStore,
+		defaults:          defaults,
+		contactsEnabled:   cfg.ContactsEnabled,
+		access:            newAccessPolicy(cfg.Access),
+		quota:             quotaTracker,
+		uploadQuotaMB:     cfg.Quota.UploadBytesPerDay >> 20,
 		logger:            log,
+		metrics:           m,
+		admin:             adminSrv,
 		maxFiles:          cfg.MaxFiles,
 		maxFileSizeMB:     cfg.MaxFileSizeMB,
 		maxTotalSizeMB:    cfg.MaxTotalSizeMB,
 		sessionTimeoutMin: cfg.SessionTimeoutMin,
+		started:           time.Now(),
+		tempDir:           cfg.TempDir,
+	}
+
+	// Файлы истёкших сессий удаляются вместе с ключом шифрования
+	sessionMgr.OnExpire(func(s session.Session) {
+		bot.deleteSessionFiles(s.ID, s.Files)
+	})
+
+	// NewBotAPI уже выполнил getMe
+	bot.lastGetMe.Store(time.Now().UnixNano())
+	if adminSrv != nil {
+		minFree := cfg.MinFreeDiskMB
+		if minFree <= 0 {
+			minFree = DefaultMinFreeDiskMB
+		}
+		bot.registerHealth(adminSrv, cfg.TempDir, minFree)
 	}
 
-	log.Info("bot initialized", "botname", api.Self.UserName)
+	log.Info("bot initialized", "botname", api.Self.UserName, "local_api", localAPI)
 	return bot, nil
 }
 
-// Start запускает бота и обрабатывает обновления
-func (b *Bot) Start() error {
+// Start запускает бота и обрабатывает обновления до отмены ctx. После отмены новые обновления
+// не принимаются, а Start дожидается обработки уже полученных, но не дольше shutdownTimeout.
+func (b *Bot) Start(ctx context.Context) error {
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
 	updates := b.api.GetUpdatesChan(u)
 
+	if b.admin != nil {
+		b.admin.Start()
+		go b.watchTelegram(ctx)
+	}
+
 	b.logger.Info("bot started, listening for updates")
 
-	for update := range updates {
-		// Обрабатываем обновление в отдельной горутине
-		go b.handleUpdate(update)
+	b.polling.Store(true)
+	defer b.polling.Store(false)
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case update, ok := <-updates:
+			if !ok {
+				break loop
+			}
+			// Обрабатываем обновление в отдельной горутине
+			b.handlers.Add(1)
+			go func() {
+				defer b.handlers.Done()
+				b.handleUpdate(update)
+			}()
+		}
 	}
 
+	b.logger.Info("stopping bot", "in_flight", b.inFlight.Load())
+	b.api.StopReceivingUpdates()
+	b.shutdown()
 	return nil
 }
 
+// shutdown дожидается обработки полученных обновлений и останавливает служебный сервер
+func (b *Bot) shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		b.handlers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		b.logger.Warn("shutdown timed out, updates still in progress", "in_flight", b.inFlight.Load())
+	}
+
+	if b.admin != nil {
+		if err := b.admin.Shutdown(ctx); err != nil {
+			b.logger.Warn("failed to shut down admin server", "error", err)
+		}
+	}
+}
+
 // handleUpdate обрабатывает одно обновление от Telegram
 func (b *Bot) handleUpdate(update tgbotapi.Update) {
+	log := b.logger
+	if update.Message != nil && update.Message.From != nil {
+		log = b.log(update.Message.From.ID)
+	}
+	log = log.With("update_id", update.UpdateID)
+	b.metrics.UpdateReceived(updateType(update))
+
+	b.inFlight.Add(1)
+	defer b.inFlight.Add(-1)
+
 	defer func() {
 		if r := recover(); r != nil {
-			b.logger.Error("recovered from panic", "error", r)
+			log.Error("recovered from panic", "error", r)
 		}
 	}()
 
 	// Обработка текстовых сообщений
 	if update.Message != nil {
-		b.handleMessage(update.Message)
+		// Доступ проверяется до команд и файлов, чтобы посторонние не создавали сессии
+		if reason := b.access.check(update.Message.From, update.Message.Chat); reason != "" {
+			log.Info("access denied", "reason", reason)
+			b.denyAccess(update.Message, reason)
+			return
+		}
+		if !b.checkRate(log, update.Message) {
+			return
+		}
+		b.handleMessage(log, update.Message)
+	}
+}
+
+// updateType возвращает тип обновления для метрик
+func updateType(update tgbotapi.Update) string {
+	switch {
+	case update.Message != nil && update.Message.IsCommand():
+		return "command"
+	case update.Message != nil && update.Message.Document != nil:
+		return "document"
+	case update.Message != nil:
+		return "message"
+	case update.EditedMessage != nil:
+		return "edited_message"
+	case update.CallbackQuery != nil:
+		return "callback_query"
+	default:
+		return "other"
 	}
 }
 
+// log возвращает логгер с полями пользователя и его сессии; user_id хэшируется логгером
+func (b *Bot) log(userID int64) *logger.Logger {
+	log := b.logger.With("user_id", userID)
+	if sess := b.sessionManager.Get(userID); sess != nil {
+		log = log.With("session_id", sess.ID)
+	}
+	return log
+}
+
 // handleMessage обрабатывает текстовое сообщение
-func (b *Bot) handleMessage(msg *tgbotapi.Message) {
+func (b *Bot) handleMessage(log *logger.Logger, msg *tgbotapi.Message) {
 	userID := int64(msg.From.ID)
 	chatID := msg.Chat.ID
 
-	b.logger.Debug("received message", "userID", userID, "text", msg.Text)
+	log.Debug("received message", "text", msg.Text)
+
+	// Запоминаем язык из профиля Telegram, чтобы отвечать на нём, пока не выбран /lang
+	if code := msg.From.LanguageCode; code != "" && b.sessionManager.GetSettings(userID).ClientLanguage != code {
+		b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+			s.ClientLanguage = code
+		})
+	}
 
 	// Обработка команд
 	if msg.IsCommand() {
-		b.handleCommand(userID, chatID, msg.Command())
+		if isAdminCommand(msg.Command()) {
+			b.handleAdminCommand(log, msg)
+			return
+		}
+		b.handleCommand(userID, chatID, msg.Command(), msg.CommandArguments())
 		return
 	}
 
@@ -139,21 +409,48 @@ func (b *Bot) handleMessage(msg *tgbotapi.Message) {
 }
 
 // handleCommand обрабатывает команды бота
-func (b *Bot) handleCommand(userID, chatID int64, command string) {
+func (b *Bot) handleCommand(userID, chatID int64, command, args string) {
 	switch command {
 	case "start":
 		b.cmdStart(userID, chatID)
 	case "help":
-		b.cmdHelp(chatID)
+		b.cmdHelp(userID, chatID)
 	case "upload":
-		b.cmdUpload(chatID)
+		b.cmdUpload(userID, chatID)
 	case "process":
 		b.cmdProcess(userID, chatID)
 	case "cancel":
 		b.cmdCancel(userID, chatID)
+	case "bots":
+		b.cmdBots(userID, chatID, args)
+	case "filter":
+		b.cmdFilter(userID, chatID, args)
+	case "settings":
+		b.cmdSettings(userID, chatID, args)
+	case "ignore":
+		b.cmdListAdd(userID, chatID, ignorelist.KindIgnore, args)
+	case "unignore":
+		b.cmdListRemove(userID, chatID, ignorelist.KindIgnore, args)
+	case "allow":
+		b.cmdListAdd(userID, chatID, ignorelist.KindAllow, args)
+	case "unallow":
+		b.cmdListRemove(userID, chatID, ignorelist.KindAllow, args)
+	case "ignorelist":
+		b.cmdIgnoreList(userID, chatID)
+	case "contacts":
+		b.cmdContacts(userID, chatID, args)
+	case "stats":
+		b.cmdStats(userID, chatID)
+	case "charts":
+		b.cmdCharts(userID, chatID, args)
+	case "lang":
+		b.cmdLang(userID, chatID, args)
 	default:
-		b.sendMessage(chatID, "Unknown command. Use /help for available commands.")
+		command = "unknown"
+		b.sendMessage(chatID, b.text(userID, MessageUnknownCommand))
 	}
+
+	b.metrics.CommandReceived(command)
 }
 
 // handleFile обрабатывает загруженный файл
@@ -161,95 +458,189 @@ func (b *Bot) handleFile(userID, chatID int64, doc *tgbotapi.Document) {
 	// Проверяем лимит файлов
 	sess := b.sessionManager.GetOrCreate(userID)
 	if len(sess.Files) >= b.maxFiles {
-		b.sendMessage(chatID, fmt.Sprintf(MessageFileLimitExceeded, len(sess.Files)))
+		b.metrics.FileRejected(metrics.RejectFileLimit)
+		b.sendMessage(chatID, b.text(userID, MessageFileLimitExceeded,
+			b.maxFiles, b.plural(userID, b.maxFiles, MessagePluralFiles),
+			len(sess.Files), b.plural(userID, len(sess.Files), MessagePluralFiles)))
 		return
 	}
 
 	// Проверяем размер файла
 	fileSizeMB := float64(doc.FileSize) / (1024 * 1024)
 	if fileSizeMB > float64(b.maxFileSizeMB) {
-		b.sendMessage(chatID, fmt.Sprintf(MessageFileSizeExceeded, fileSizeMB))
+		b.metrics.FileRejected(metrics.RejectFileSize)
+		b.sendMessage(chatID, b.text(userID, MessageFileSizeExceeded, b.maxFileSizeMB, fileSizeMB))
 		return
 	}
 
-	// Скачиваем файл
-	fileURL, err := b.api.GetFileDirectURL(doc.FileID)
-	if err != nil {
-		b.logger.Error("failed to get file URL", "error", err)
-		b.sendMessage(chatID, MessageUnexpectedError)
+	// Проверяем общий размер файлов сессии
+	totalSizeMB := float64(sess.TotalSize+int64(doc.FileSize)) / (1024 * 1024)
+	if totalSizeMB > float64(b.maxTotalSizeMB) {
+		b.metrics.FileRejected(metrics.RejectTotalSize)
+		b.sendMessage(chatID, b.text(userID, MessageTotalSizeExceeded,
+			b.maxTotalSizeMB, float64(sess.TotalSize)/(1024*1024), fileSizeMB))
 		return
 	}
 
-	// Загружаем файл
-	resp, err := http.Get(fileURL)
-	if err != nil {
-		b.logger.Error("failed to download file", "error", err)
-		b.sendMessage(chatID, MessageUnexpectedError)
+	// Проверяем суточную квоту загрузок до скачивания
+	if !b.checkUploadQuota(userID, chatID, int64(doc.FileSize)) {
+		b.metrics.FileRejected(metrics.RejectQuota)
 		return
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			b.logger.Error("failed to close response body", "error", err)
-		}
-	}()
 
-	// Сохраняем в временное хранилище
 	filename := doc.FileName
 	if filename == "" {
 		filename = fmt.Sprintf("export_%d.json", userID)
 	}
 
-	filePath, err := b.tempStorage.Save(filename, resp.Body)
+	// Скачиваем файл во временное хранилище
+	filePath, reason, err := b.downloadFile(sess.ID, doc, filename)
 	if err != nil {
-		b.logger.Error("failed to save temp file", "error", err)
-		b.sendMessage(chatID, MessageUnexpectedError)
+		b.log(userID).Error("failed to download file", "error", err)
+		b.metrics.FileRejected(reason)
+		b.sendMessage(chatID, b.text(userID, MessageUnexpectedError))
 		return
 	}
 
 	// Добавляем в сессию
-	b.sessionManager.AddFile(userID, filePath)
-	b.sendMessage(chatID, fmt.Sprintf(MessageFileReceived, filename))
-	b.sendMessage(chatID, fmt.Sprintf(MessageFilesReady, len(sess.Files), fileSizeMB))
+	sess, _ = b.sessionManager.AddFile(userID, filePath, int64(doc.FileSize))
+	b.metrics.FileUploaded()
+	b.sendMessage(chatID, b.text(userID, MessageFileReceived, filename))
+	b.sendMessage(chatID, b.text(userID, MessageFilesReady, len(sess.Files), float64(sess.TotalSize)/(1024*1024)))
+}
+
+// downloadFile скачивает документ из хранилища Telegram во временное хранилище.
+// При ошибке возвращает причину отклонения файла для метрик.
+func (b *Bot) downloadFile(sessionID string, doc *tgbotapi.Document, filename string) (filePath, reason string, err error) {
+	_, span := tracer.Start(context.Background(), "telegram.DownloadFile", trace.WithAttributes(
+		attribute.Int("file.size", doc.FileSize),
+		attribute.String("file.format", metrics.FileFormat(filename)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	file, err := b.api.GetFile(tgbotapi.FileConfig{FileID: doc.FileID})
+	if err != nil {
+		return "", metrics.RejectDownload, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	src, local, err := b.openFile(file.FilePath)
+	span.SetAttributes(attribute.Bool("file.local", local))
+	if err != nil {
+		return "", metrics.RejectDownload, err
+	}
+	defer func() {
+		if err := src.Close(); err != nil {
+			b.logger.Error("failed to close file source", "error", err)
+		}
+	}()
+
+	body := &countingReader{r: src}
+	filePath, err = b.tempStorage.Session(sessionID).Save(filename, body)
+	b.metrics.BytesDownloaded(body.n)
+	span.SetAttributes(attribute.Int64("downloaded.bytes", body.n))
+	if err != nil {
+		return "", metrics.RejectStorage, fmt.Errorf("failed to save temp file: %w", err)
+	}
+
+	// Копия сервера больше не нужна: файлы экспорта не должны оставаться на диске
+	if local {
+		if err := os.Remove(file.FilePath); err != nil {
+			b.logger.Warn("failed to remove file from local Bot API server", "error", err)
+		}
+	}
+	return filePath, "", nil
+}
+
+// openFile открывает файл, полученный через getFile. Сервер telegram-bot-api в режиме --local
+// возвращает абсолютный путь в своей файловой системе (общий том с ботом), облачный API и
+// сервер без --local — относительный путь для скачивания по HTTP.
+func (b *Bot) openFile(path string) (src io.ReadCloser, local bool, err error) {
+	if b.localAPI && filepath.IsAbs(path) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to open local file: %w", err)
+		}
+		return f, true, nil
+	}
+
+	resp, err := http.Get(fmt.Sprintf(b.fileEndpoint, b.api.Token, path))
+	if err != nil {
+		// Ссылка на файл содержит токен бота, поэтому в ошибку попадает только причина
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, false, fmt.Errorf("failed to download file: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, false, fmt.Errorf("failed to download file: unexpected status %s", resp.Status)
+	}
+	return resp.Body, false, nil
 }
 
 // cmdStart обрабатывает команду /start
 func (b *Bot) cmdStart(userID, chatID int64) {
 	sess := b.sessionManager.Get(userID)
 	if sess != nil && len(sess.Files) > 0 {
-		b.sendMessage(chatID, MessageWelcomeBack)
+		b.sendMessage(chatID, b.text(userID, MessageWelcomeBack))
 	} else {
-		b.sendMessage(chatID, MessageStart)
+		threshold := b.exportSvc.ListThreshold()
+		b.sendMessage(chatID, b.text(userID, MessageStart, b.maxFiles, threshold-1, threshold))
 	}
 }
 
 // cmdHelp обрабатывает команду /help
-func (b *Bot) cmdHelp(chatID int64) {
-	b.sendMessage(chatID, MessageHelp)
+func (b *Bot) cmdHelp(userID, chatID int64) {
+	b.sendMessage(chatID, b.text(userID, MessageHelp,
+		b.maxFiles, b.plural(userID, b.maxFiles, MessagePluralFiles),
+		b.maxFiles,
+		b.maxFileSizeMB,
+		b.maxTotalSizeMB,
+		b.sessionDuration(userID)))
+}
+
+// sessionDuration описывает время жизни сессии: целыми часами, если возможно, иначе минутами
+func (b *Bot) sessionDuration(userID int64) string {
+	minutes := b.sessionTimeoutMin
+	if minutes >= 60 && minutes%60 == 0 {
+		hours := minutes / 60
+		return fmt.Sprintf("%d %s", hours, b.plural(userID, hours, MessagePluralHours))
+	}
+	return fmt.Sprintf("%d %s", minutes, b.plural(userID, minutes, MessagePluralMinutes))
 }
 
 // cmdUpload обрабатывает команду /upload
-func (b *Bot) cmdUpload(chatID int64) {
-	b.sendMessage(chatID, MessageUploadPrompt)
+func (b *Bot) cmdUpload(userID, chatID int64) {
+	b.sendMessage(chatID, b.text(userID, MessageUploadPrompt, b.maxFiles, b.maxFileSizeMB))
 }
 
 // cmdProcess обрабатывает команду /process
 func (b *Bot) cmdProcess(userID, chatID int64) {
 	sess := b.sessionManager.Get(userID)
 	if sess == nil || len(sess.Files) == 0 {
-		b.sendMessage(chatID, MessageNoFiles)
+		b.sendMessage(chatID, b.text(userID, MessageNoFiles))
+		return
+	}
+	if !b.checkProcessQuota(userID, chatID) {
 		return
 	}
 
 	b.sessionManager.SetState(userID, session.StateProcessing)
-	b.sendMessage(chatID, fmt.Sprintf(MessageProcessing, len(sess.Files)))
+	b.sendMessage(chatID, b.text(userID, MessageProcessing, len(sess.Files), b.plural(userID, len(sess.Files), MessagePluralFiles)))
 
 	// Обрабатываем файлы
-	b.processFiles(userID, chatID, sess.Files)
+	ctx, span := tracer.Start(context.Background(), "telegram.Process",
+		trace.WithAttributes(attribute.Int("files.count", len(sess.Files))))
+	start := time.Now()
+	b.processFiles(ctx, userID, chatID, sess.Files)
+	b.metrics.ProcessingFinished(start)
+	span.End()
 
 	// Очищаем сессию и удаляем временные файлы
 	defer func() {
-		b.tempStorage.DeleteAll(sess.Files)
-		b.sessionManager.Clear(userID)
+		b.deleteSessionFiles(sess.ID, sess.Files)
+		b.sessionManager.ClearFiles(userID)
 	}()
 }
 
@@ -257,42 +648,57 @@ func (b *Bot) cmdProcess(userID, chatID int64) {
 func (b *Bot) cmdCancel(userID, chatID int64) {
 	sess := b.sessionManager.Get(userID)
 	if sess == nil || len(sess.Files) == 0 {
-		b.sendMessage(chatID, MessageNothingToCancel)
+		b.sendMessage(chatID, b.text(userID, MessageNothingToCancel))
 		return
 	}
 
 	// Удаляем все временные файлы
-	b.tempStorage.DeleteAll(sess.Files)
-	b.sessionManager.Clear(userID)
+	b.deleteSessionFiles(sess.ID, sess.Files)
+	b.sessionManager.ClearFiles(userID)
 
-	b.sendMessage(chatID, MessageCancelled)
+	b.sendMessage(chatID, b.text(userID, MessageCancelled))
 }
 
-// processFiles обрабатывает загруженные файлы
-func (b *Bot) processFiles(userID, chatID int64, filePaths []string) {
+// loadEvents читает и парсит загруженные файлы, применяя фильтр сессии.
+// При ошибке сообщение пользователю уже отправлено, и возвращается ok == false.
+func (b *Bot) loadEvents(ctx context.Context, userID, chatID int64, filePaths []string) (events []parser.Event, index metadata.Index, spec filter.Spec, ok bool) {
 	var allEvents []parser.Event
+	var indexes []metadata.Index
+	files := b.tempStorage.Session(b.sessionManager.GetOrCreate(userID).ID)
 
 	// Парсим все файлы
 	for _, filePath := range filePaths {
-		f, err := b.tempStorage.Read(filePath)
+		data, err := b.readFile(files, filePath)
 		if err != nil {
-			b.logger.Error("failed to read file", "error", err)
+			b.log(userID).Error("failed to read file", "error", err)
 			filename := filepath.Base(filePath)
-			b.sendMessage(chatID, fmt.Sprintf(MessageFileParseError, filename, "Unable to read file"))
-			return
+			b.sendMessage(chatID, b.text(userID, MessageFileParseError, filename, safeHTML(b.text(userID, MessageFileUnreadable))))
+			return nil, metadata.Index{}, spec, false
 		}
-		defer func() {
-			if err := f.Close(); err != nil {
-				b.logger.Error("failed to close file", "error", err)
-			}
-		}()
 
-		events, err := parser.ParseFile(f, filePath)
+		_, span := tracer.Start(ctx, "parser.ParseFile", trace.WithAttributes(
+			attribute.Int("file.size", len(data)),
+			attribute.String("file.format", metrics.FileFormat(filePath)),
+		))
+		events, err := parser.ParseFile(bytes.NewReader(data), filePath)
+		span.SetAttributes(attribute.Int("events.count", len(events)))
+		endSpan(span, err)
 		if err != nil {
-			b.logger.Error("failed to parse file", "error", err)
+			b.log(userID).Error("failed to parse file", "error", err)
+			b.metrics.ParseFailed(filePath)
 			filename := filepath.Base(filePath)
-			b.sendMessage(chatID, fmt.Sprintf(MessageFileParseError, filename, err.Error()))
-			return
+			b.sendMessage(chatID, b.text(userID, MessageFileParseError, filename, err.Error()))
+			return nil, metadata.Index{}, spec, false
+		}
+
+		// Метаданные (ответы, пересылки) не критичны для результата
+		_, span = tracer.Start(ctx, "metadata.Parse", trace.WithAttributes(attribute.Int("file.size", len(data))))
+		index, err := metadata.Parse(data, filePath)
+		endSpan(span, err)
+		if err != nil {
+			b.log(userID).Warn("failed to parse message metadata", "error", err)
+		} else {
+			indexes = append(indexes, index)
 		}
 
 		allEvents = append(allEvents, events...)
@@ -300,64 +706,203 @@ func (b *Bot) processFiles(userID, chatID int64, filePaths []string) {
 
 	// Объединяем события
 	if len(allEvents) == 0 {
-		b.sendMessage(chatID, MessageNoParticipants)
-		return
+		b.sendMessage(chatID, b.text(userID, MessageNoParticipants))
+		return nil, metadata.Index{}, spec, false
+	}
+
+	spec = b.sessionManager.GetSettings(userID).Filter
+
+	_, span := tracer.Start(ctx, "parser.MergeEvents")
+	index = metadata.Merge(indexes...)
+	mergedEvents := spec.Events(allEvents, index)
+	span.SetAttributes(attribute.Int("events.count", len(mergedEvents)))
+	span.End()
+
+	// Применяем фильтр пользователя до извлечения
+	_, span = tracer.Start(ctx, "filter.Apply", trace.WithAttributes(attribute.Int("events.in", len(mergedEvents))))
+	mergedEvents = spec.Apply(mergedEvents, index)
+	span.SetAttributes(attribute.Int("events.out", len(mergedEvents)))
+	span.End()
+	if len(mergedEvents) == 0 {
+		b.sendMessage(chatID, b.text(userID, MessageNoEventsForFilter, b.filterText(userID, spec)))
+		return nil, metadata.Index{}, spec, false
 	}
 
-	mergedEvents := parser.MergeEvents([][]parser.Event{allEvents})
+	return mergedEvents, index, spec, true
+}
+
+// processFiles обрабатывает загруженные файлы
+func (b *Bot) processFiles(ctx context.Context, userID, chatID int64, filePaths []string) {
+	mergedEvents, index, spec, ok := b.loadEvents(ctx, userID, chatID, filePaths)
+	if !ok {
+		return
+	}
 
 	// Извлекаем участников
-	result, err := b.participantSvc.Extract(mergedEvents)
+	result, err := b.newExtractor(userID).Extract(ctx, mergedEvents, index)
 	if err != nil {
-		b.logger.Error("failed to extract participants", "error", err)
-		b.sendMessage(chatID, fmt.Sprintf(MessageProcessingError, err.Error()))
+		b.log(userID).Error("failed to extract participants", "error", err)
+		b.sendMessage(chatID, b.text(userID, MessageProcessingError, err.Error()))
 		return
 	}
 
+	b.metrics.ResultParticipants(len(result.Participants))
 	if len(result.Participants) == 0 {
-		b.sendMessage(chatID, MessageNoParticipants)
+		b.sendMessage(chatID, b.text(userID, MessageNoParticipants))
 		return
 	}
 
+	// Строим граф ответов и пересылок
+	_, span := tracer.Start(ctx, "interaction.Analyze")
+	graph := b.interactionSvc.Analyze(mergedEvents, index)
+	span.End()
+
 	// Отправляем статистику
-	b.sendMessage(chatID, fmt.Sprintf(MessageResultReady,
+	summary := b.text(userID, MessageResultReady,
 		len(result.Participants),
+		countKind(result, participant.KindBot),
 		len(result.Mentions),
 		len(result.Channels),
-		len(mergedEvents)))
+		len(mergedEvents))
+	if !spec.IsEmpty() {
+		summary += b.text(userID, MessageActiveFilter, b.filterText(userID, spec))
+	}
+	b.sendMessage(chatID, summary)
+
+	report := export.Report{
+		Result:       result,
+		Interactions: graph,
+		Filter:       spec,
+		Activity:     stats.Compute(mergedEvents, index),
+		Labels:       b.reportLabels(userID),
+	}
+
+	if !b.sessionManager.GetSettings(userID).HideCharts {
+		b.sendCharts(userID, chatID, report.Activity, report.Labels.Stats)
+	}
 
 	// Выбираем формат и экспортируем
 	format := b.exportSvc.ChooseFormat(len(result.Participants))
 
 	switch format {
 	case exporter.OutputExcel:
-		b.sendExcelResult(chatID, result)
+		b.sendExcelResult(ctx, userID, chatID, report)
 	case exporter.OutputTelegramList:
-		b.sendListResult(chatID, result)
+		b.sendListResult(userID, chatID, result)
+	}
+
+	b.sendHTMLReport(ctx, userID, chatID, report)
+
+	if !graph.Empty() {
+		b.sendInteractions(userID, chatID, graph)
+	}
+
+	if len(result.Hashtags) > 0 || len(result.Domains) > 0 || len(result.Contacts) > 0 {
+		b.sendContent(userID, chatID, result)
 	}
 
 	b.sessionManager.SetState(userID, session.StateComplete)
 }
 
+// newExtractor создаёт экстрактор участников с учётом настроек сессии пользователя
+func (b *Bot) newExtractor(userID int64) participant.Extractor {
+	settings := b.sessionManager.GetSettings(userID)
+	effective := b.effectiveDefaults(settings)
+
+	// Без списков игнорирования анализ всё равно возможен
+	list, err := b.ignoreStore.Load(userID)
+	if err != nil {
+		b.log(userID).Warn("failed to load ignore list", "error", err)
+	}
+
+	return participant.New(
+		participant.WithMinMessages(effective.MinMessages),
+		participant.WithIncludeMentions(effective.IncludeMentions),
+		participant.WithExcludeUsers(effective.ExcludeUsers...),
+		participant.WithCaseSensitive(effective.CaseSensitive),
+		participant.WithExcludeBots(settings.ExcludeBots),
+		participant.WithIncludeChannels(settings.IncludeChannels),
+		participant.WithIgnoreList(list),
+		participant.WithContacts(b.contactsEnabled && settings.ExtractContacts),
+	)
+}
+
+// countKind считает участников заданного типа
+func countKind(result participant.Result, kind participant.Kind) int {
+	count := 0
+	for _, p := range result.Participants {
+		if result.KindOf(p) == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// endSpan завершает спан этапа. В спан попадает только тип ошибки:
+// текст ошибок парсинга может содержать данные из файла пользователя.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(codes.Error, "stage failed")
+		span.SetAttributes(attribute.String("error.type", fmt.Sprintf("%T", err)))
+	}
+	span.End()
+}
+
+// countingReader считает прочитанные байты для метрики скачанного объёма
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// readFile читает и расшифровывает временный файл целиком
+func (b *Bot) readFile(files storage.TempStorage, filePath string) ([]byte, error) {
+	f, err := files.Read(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			b.logger.Error("failed to close file", "error", err)
+		}
+	}()
+
+	return io.ReadAll(f)
+}
+
+// deleteSessionFiles удаляет временные файлы сессии и забывает её ключ шифрования:
+// файлы, которые не удалось удалить, остаются нечитаемыми
+func (b *Bot) deleteSessionFiles(sessionID string, files []string) {
+	if err := b.tempStorage.Session(sessionID).DeleteAll(files); err != nil {
+		b.logger.Error("failed to delete temp files", "session_id", sessionID, "error", err)
+	}
+	b.tempStorage.Forget(sessionID)
+}
+
 // sendListResult отправляет результат в виде списка в чат
-func (b *Bot) sendListResult(chatID int64, result exporter.ParticipantsResult) {
-	messages := b.exportSvc.FormatForTelegram(result.Participants)
+func (b *Bot) sendListResult(userID, chatID int64, result participant.Result) {
+	messages := b.exportSvc.FormatResultForTelegram(result, b.kindLabels(userID))
 
 	for i, msg := range messages {
 		if len(messages) > 1 {
-			b.sendMessage(chatID, fmt.Sprintf(MessageListTruncated, i+1, len(messages), msg))
+			b.sendMessage(chatID, b.text(userID, MessageListTruncated, i+1, len(messages), msg))
 		} else {
-			b.sendMessage(chatID, fmt.Sprintf(MessageListReady, msg))
+			b.sendMessage(chatID, b.text(userID, MessageListReady, msg))
 		}
 	}
 }
 
 // sendExcelResult отправляет результат в виде Excel файла
-func (b *Bot) sendExcelResult(chatID int64, result exporter.ParticipantsResult) {
-	data, err := b.exportSvc.ExportToExcel(result)
+func (b *Bot) sendExcelResult(ctx context.Context, userID, chatID int64, report export.Report) {
+	data, err := b.exportSvc.ExportReportToExcel(ctx, report)
 	if err != nil {
-		b.logger.Error("failed to export to Excel", "error", err)
-		b.sendMessage(chatID, fmt.Sprintf(MessageProcessingError, err.Error()))
+		b.log(userID).Error("failed to export to Excel", "error", err)
+		b.sendMessage(chatID, b.text(userID, MessageProcessingError, err.Error()))
 		return
 	}
 
@@ -368,20 +913,193 @@ func (b *Bot) sendExcelResult(chatID int64, result exporter.ParticipantsResult)
 	}
 
 	msg := tgbotapi.NewDocument(chatID, fileBytes)
-	msg.Caption = MessageExcelReady
+	msg.Caption = b.text(userID, MessageExcelReady)
+
+	if _, err := b.sender.Send(chatID, msg); err != nil {
+		b.log(userID).Error("failed to send Excel file", "error", err)
+		b.sendMessage(chatID, b.text(userID, MessageUnexpectedError))
+	}
+}
+
+// sendHTMLReport отправляет автономный HTML отчёт с таблицами и графиками
+func (b *Bot) sendHTMLReport(ctx context.Context, userID, chatID int64, report export.Report) {
+	data, err := b.exportSvc.ExportToHTML(ctx, report)
+	if err != nil {
+		b.log(userID).Error("failed to export to HTML", "error", err)
+		return
+	}
+
+	msg := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: "report.html", Bytes: data})
+	msg.Caption = b.text(userID, MessageHTMLReady)
 
-	if _, err := b.api.Send(msg); err != nil {
-		b.logger.Error("failed to send Excel file", "error", err)
-		b.sendMessage(chatID, MessageUnexpectedError)
+	if _, err := b.sender.Send(chatID, msg); err != nil {
+		b.log(userID).Error("failed to send HTML report", "error", err)
 	}
 }
 
-// sendMessage отправляет текстовое сообщение
+// sendInteractions отправляет сводку по ответам и пересылкам и граф в форматах GraphML и DOT
+func (b *Bot) sendInteractions(userID, chatID int64, graph interaction.Graph) {
+	b.sendMessage(chatID, b.text(userID, MessageInteractionsSummary,
+		formatEdges(graph, interaction.EdgeReply, b.text(userID, MessageInteractionsNone)),
+		formatSources(graph, b.text(userID, MessageInteractionsNone))))
+
+	files := []tgbotapi.FileBytes{
+		{Name: "interactions.graphml", Bytes: b.exportSvc.ExportGraphML(graph)},
+		{Name: "interactions.dot", Bytes: b.exportSvc.ExportDOT(graph)},
+	}
+
+	for _, file := range files {
+		msg := tgbotapi.NewDocument(chatID, file)
+		msg.Caption = b.text(userID, MessageGraphReady)
+
+		if _, err := b.sender.Send(chatID, msg); err != nil {
+			b.log(userID).Error("failed to send interaction graph", "error", err)
+		}
+	}
+}
+
+// sendContent отправляет сводку по хэштегам и доменам и CSV файлы одной медиагруппой
+func (b *Bot) sendContent(userID, chatID int64, result participant.Result) {
+	hashtags := make([]string, 0, topInteractionsLimit)
+	for i, h := range result.Hashtags {
+		if i == topInteractionsLimit {
+			break
+		}
+		hashtags = append(hashtags, fmt.Sprintf("• %s: %d", h.Tag, h.Count))
+	}
+	domains := make([]string, 0, topInteractionsLimit)
+	for i, d := range result.Domains {
+		if i == topInteractionsLimit {
+			break
+		}
+		domains = append(domains, fmt.Sprintf("• %s: %d", d.Domain, d.Count))
+	}
+	if len(hashtags) == 0 {
+		hashtags = append(hashtags, b.text(userID, MessageInteractionsNone))
+	}
+	if len(domains) == 0 {
+		domains = append(domains, b.text(userID, MessageInteractionsNone))
+	}
+
+	b.sendMessage(chatID, b.text(userID, MessageContentSummary,
+		len(result.Hashtags), strings.Join(hashtags, "\n"),
+		len(result.Links), len(result.Domains), strings.Join(domains, "\n")))
+
+	if len(result.Contacts) > 0 {
+		b.sendMessage(chatID, b.text(userID, MessageContactsExported, len(result.Contacts)))
+	}
+
+	files, err := b.exportSvc.ExportContentCSV(result)
+	if err != nil {
+		b.log(userID).Error("failed to export content CSV", "error", err)
+		return
+	}
+
+	media := make([]interface{}, 0, len(files))
+	for _, file := range files {
+		media = append(media, tgbotapi.NewInputMediaDocument(tgbotapi.FileBytes{Name: file.Name, Bytes: file.Bytes}))
+	}
+
+	// Telegram не принимает медиагруппу из одного файла
+	if len(media) == 1 {
+		msg := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: files[0].Name, Bytes: files[0].Bytes})
+		if _, err := b.sender.Send(chatID, msg); err != nil {
+			b.log(userID).Error("failed to send content CSV", "error", err)
+		}
+		return
+	}
+
+	if _, err := b.sender.SendMediaGroup(tgbotapi.NewMediaGroup(chatID, media)); err != nil {
+		b.log(userID).Error("failed to send content CSV", "error", err)
+	}
+}
+
+// sendCharts отправляет графики активности и рейтинга участников одной медиагруппой
+func (b *Bot) sendCharts(userID, chatID int64, activity stats.Report, labels stats.Labels) {
+	images, err := charts.Render(activity, charts.FormatPNG, labels)
+	if err != nil {
+		b.log(userID).Error("failed to render charts", "error", err)
+		return
+	}
+	if len(images) == 0 {
+		return
+	}
+
+	// Telegram не принимает медиагруппу из одного файла
+	if len(images) == 1 {
+		msg := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: images[0].Name, Bytes: images[0].Bytes})
+		msg.Caption = b.text(userID, MessageChartsReady)
+		if _, err := b.sender.Send(chatID, msg); err != nil {
+			b.log(userID).Error("failed to send chart", "error", err)
+		}
+		return
+	}
+
+	media := make([]interface{}, 0, len(images))
+	for i, image := range images {
+		photo := tgbotapi.NewInputMediaPhoto(tgbotapi.FileBytes{Name: image.Name, Bytes: image.Bytes})
+		if i == 0 {
+			photo.Caption = b.text(userID, MessageChartsReady)
+		}
+		media = append(media, photo)
+	}
+
+	if _, err := b.sender.SendMediaGroup(tgbotapi.NewMediaGroup(chatID, media)); err != nil {
+		b.log(userID).Error("failed to send charts", "error", err)
+	}
+}
+
+// formatEdges формирует топ связей заданного типа для текстовой сводки
+func formatEdges(graph interaction.Graph, kind interaction.EdgeKind, none string) string {
+	edges := graph.TopEdges(kind, topInteractionsLimit)
+	if len(edges) == 0 {
+		return none
+	}
+
+	label := graph.Labeler()
+	lines := make([]string, 0, len(edges))
+	for _, e := range edges {
+		lines = append(lines, fmt.Sprintf("• %s → %s: %d", label(e.From), label(e.To), e.Weight))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatSources формирует топ источников пересылок для текстовой сводки
+func formatSources(graph interaction.Graph, none string) string {
+	sources := graph.Sources
+	if len(sources) > topInteractionsLimit {
+		sources = sources[:topInteractionsLimit]
+	}
+	if len(sources) == 0 {
+		return none
+	}
+
+	lines := make([]string, 0, len(sources))
+	for _, src := range sources {
+		name := src.Name
+		if name == "" {
+			name = src.ID
+		}
+		lines = append(lines, fmt.Sprintf("• %s: %d", name, src.Count))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sendMessage отправляет сообщение в HTML разметке; пользовательские данные должны быть
+// подставлены через formatHTML. Если Telegram не смог разобрать разметку, сообщение
+// отправляется повторно обычным текстом.
 func (b *Bot) sendMessage(chatID int64, text string) {
 	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = "Markdown"
+	msg.ParseMode = parseModeHTML
 
-	if _, err := b.api.Send(msg); err != nil {
+	_, err := b.sender.Send(chatID, msg)
+	if err != nil && isParseError(err) {
+		b.logger.Warn("failed to parse message entities, resending as plain text", "error", err)
+
+		msg = tgbotapi.NewMessage(chatID, plainText(text))
+		_, err = b.sender.Send(chatID, msg)
+	}
+	if err != nil {
 		b.logger.Error("failed to send message", "error", err)
 	}
 }
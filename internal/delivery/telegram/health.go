@@ -0,0 +1,71 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/delivery/admin"
+)
+
+const (
+	// DefaultMinFreeDiskMB минимум свободного места в TEMP_DIR для готовности принимать файлы
+	DefaultMinFreeDiskMB = 100
+
+	// getMeInterval период проверки доступности Bot API
+	getMeInterval = 30 * time.Second
+	// getMeMaxAge возраст последнего успешного getMe, после которого бот считается неготовым
+	getMeMaxAge = 2 * time.Minute
+)
+
+// registerHealth добавляет на служебный сервер /healthz (процесс жив, цикл обновлений работает)
+// и /readyz (Bot API доступен, TEMP_DIR доступен для записи, на диске достаточно места)
+func (b *Bot) registerHealth(srv *admin.Server, tempDir string, minFreeDiskMB int) {
+	srv.Handle("/healthz", admin.HealthHandler(map[string]admin.Check{
+		"updates": b.checkPolling,
+	}))
+	srv.Handle("/readyz", admin.HealthHandler(map[string]admin.Check{
+		"telegram": b.checkTelegram,
+		"temp_dir": admin.WritableDir(tempDir),
+		"disk":     admin.FreeSpace(tempDir, uint64(minFreeDiskMB)<<20),
+	}))
+}
+
+// checkPolling проверяет, что цикл получения обновлений запущен
+func (b *Bot) checkPolling(context.Context) error {
+	if !b.polling.Load() {
+		return errors.New("update loop is not running")
+	}
+	return nil
+}
+
+// checkTelegram проверяет, что getMe недавно выполнялся успешно
+func (b *Bot) checkTelegram(context.Context) error {
+	age := time.Since(time.Unix(0, b.lastGetMe.Load()))
+	if age > getMeMaxAge {
+		return fmt.Errorf("last successful getMe was %s ago", age.Round(time.Second))
+	}
+	return nil
+}
+
+// watchTelegram периодически вызывает getMe и запоминает время последнего успешного ответа
+func (b *Bot) watchTelegram(ctx context.Context) {
+	ticker := time.NewTicker(getMeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := b.api.GetMe(); err != nil {
+			b.logger.Warn("telegram getMe failed", "error", err)
+			b.metrics.APIError(errorCode(err))
+			continue
+		}
+		b.lastGetMe.Store(time.Now().UnixNano())
+	}
+}
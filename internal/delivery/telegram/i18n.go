@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"strings"
+)
+
+// Lang язык сообщений бота
+type Lang string
+
+const (
+	LangRU Lang = "ru"
+	LangEN Lang = "en"
+
+	// DefaultLang язык, если Telegram не сообщил язык пользователя
+	DefaultLang = LangRU
+)
+
+// catalogs тексты сообщений по языкам
+var catalogs = map[Lang]map[MessageID]string{
+	LangRU: messagesRU,
+	LangEN: messagesEN,
+}
+
+// russianSpeaking языки, пользователям которых показываются сообщения на русском
+var russianSpeaking = map[string]bool{
+	"ru": true,
+	"uk": true,
+	"be": true,
+	"kk": true,
+}
+
+// ParseLang разбирает язык, выбранный командой /lang
+func ParseLang(value string) (Lang, bool) {
+	lang := Lang(strings.ToLower(strings.TrimSpace(value)))
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// DetectLang выбирает язык по коду из профиля Telegram (IETF тег, например "en-US")
+func DetectLang(languageCode string) Lang {
+	code := strings.ToLower(strings.TrimSpace(languageCode))
+	if code == "" {
+		return DefaultLang
+	}
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i]
+	}
+
+	if lang, ok := ParseLang(code); ok {
+		return lang
+	}
+	if russianSpeaking[code] {
+		return LangRU
+	}
+	return LangEN
+}
+
+// translate возвращает шаблон сообщения; при отсутствии перевода используется язык по умолчанию
+func translate(lang Lang, id MessageID) string {
+	if text, ok := catalogs[lang][id]; ok {
+		return text
+	}
+	if text, ok := catalogs[DefaultLang][id]; ok {
+		return text
+	}
+	return string(id)
+}
+
+// plural выбирает форму слова для числа n. Формы в каталоге перечисляются через "|":
+// для русского "файл|файла|файлов", для английского "file|files".
+func plural(lang Lang, n int, id MessageID) string {
+	forms := strings.Split(translate(lang, id), "|")
+
+	var index int
+	switch lang {
+	case LangRU:
+		index = pluralRU(n)
+	default:
+		if n != 1 {
+			index = 1
+		}
+	}
+
+	if index >= len(forms) {
+		index = len(forms) - 1
+	}
+	return forms[index]
+}
+
+// pluralRU возвращает индекс формы по правилам русского языка: 1 файл, 2 файла, 5 файлов
+func pluralRU(n int) int {
+	if n < 0 {
+		n = -n
+	}
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// lang возвращает язык пользователя: выбранный командой /lang или язык из профиля Telegram
+func (b *Bot) lang(userID int64) Lang {
+	settings := b.sessionManager.GetSettings(userID)
+	if lang, ok := ParseLang(settings.Language); ok {
+		return lang
+	}
+	return DetectLang(settings.ClientLanguage)
+}
+
+// text возвращает сообщение на языке пользователя, подставляя аргументы через formatHTML
+func (b *Bot) text(userID int64, id MessageID, args ...interface{}) string {
+	return formatHTML(translate(b.lang(userID), id), args...)
+}
+
+// plural возвращает форму слова для числа n на языке пользователя
+func (b *Bot) plural(userID int64, n int, id MessageID) string {
+	return plural(b.lang(userID), n, id)
+}
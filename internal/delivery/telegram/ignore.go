@@ -0,0 +1,95 @@
+package telegram
+
+import (
+	"strings"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/ignorelist"
+)
+
+// cmdListAdd обрабатывает команды /ignore и /allow: добавляет правила в список пользователя
+func (b *Bot) cmdListAdd(userID, chatID int64, kind ignorelist.Kind, args string) {
+	rules := strings.Fields(args)
+	if len(rules) == 0 {
+		b.sendMessage(chatID, b.text(userID, MessageIgnoreUsage))
+		return
+	}
+
+	var list ignorelist.List
+	for _, raw := range rules {
+		var err error
+		if list, err = b.ignoreStore.Add(userID, kind, raw); err != nil {
+			b.log(userID).Warn("failed to add ignore rule", "error", err)
+			b.sendMessage(chatID, b.text(userID, MessageIgnoreInvalid, raw))
+			return
+		}
+	}
+
+	if kind == ignorelist.KindAllow {
+		// Исключения только отменяют правила /ignore: без них они ни на что не влияют
+		if len(list.Ignore) == 0 {
+			b.sendMessage(chatID, b.text(userID, MessageAllowWithoutIgnore, strings.Join(rules, ", ")))
+			return
+		}
+		b.sendMessage(chatID, b.text(userID, MessageAllowAdded, strings.Join(rules, ", ")))
+	} else {
+		b.sendMessage(chatID, b.text(userID, MessageIgnoreAdded, strings.Join(rules, ", ")))
+	}
+}
+
+// cmdListRemove обрабатывает команды /unignore и /unallow: удаляет правила из списка пользователя
+func (b *Bot) cmdListRemove(userID, chatID int64, kind ignorelist.Kind, args string) {
+	rules := strings.Fields(args)
+	if len(rules) == 0 {
+		b.sendMessage(chatID, b.text(userID, MessageIgnoreUsage))
+		return
+	}
+
+	var missing []string
+	for _, raw := range rules {
+		_, removed, err := b.ignoreStore.Remove(userID, kind, raw)
+		if err != nil {
+			b.log(userID).Warn("failed to remove ignore rule", "error", err)
+			b.sendMessage(chatID, b.text(userID, MessageIgnoreInvalid, raw))
+			return
+		}
+		if !removed {
+			missing = append(missing, raw)
+		}
+	}
+
+	if len(missing) > 0 {
+		b.sendMessage(chatID, b.text(userID, MessageIgnoreNotFound, strings.Join(missing, ", ")))
+		return
+	}
+	b.sendMessage(chatID, b.text(userID, MessageIgnoreRemoved, strings.Join(rules, ", ")))
+}
+
+// cmdIgnoreList обрабатывает команду /ignorelist: показывает списки пользователя
+func (b *Bot) cmdIgnoreList(userID, chatID int64) {
+	list, err := b.ignoreStore.Load(userID)
+	if err != nil {
+		b.log(userID).Error("failed to load ignore list", "error", err)
+		b.sendMessage(chatID, b.text(userID, MessageUnexpectedError))
+		return
+	}
+
+	if list.IsEmpty() {
+		b.sendMessage(chatID, b.text(userID, MessageIgnoreListEmpty))
+		return
+	}
+
+	b.sendMessage(chatID, b.text(userID, MessageIgnoreList, formatRules(list.Ignore, b.text(userID, MessageInteractionsNone)), formatRules(list.Allow, b.text(userID, MessageInteractionsNone))))
+}
+
+// formatRules форматирует правила списком
+func formatRules(rules []ignorelist.Rule, none string) safeHTML {
+	if len(rules) == 0 {
+		return safeHTML(none)
+	}
+
+	lines := make([]string, 0, len(rules))
+	for _, r := range rules {
+		lines = append(lines, "• "+string(codeHTML(r.String())))
+	}
+	return safeHTML(strings.Join(lines, "\n"))
+}
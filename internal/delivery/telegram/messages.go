@@ -1,171 +1,174 @@
 package telegram
 
-// Все сообщения бота на русском языке
+// MessageID идентификатор сообщения в каталоге; тексты на каждом языке хранятся
+// в messages_ru.go и messages_en.go
+type MessageID string
 
 const (
 	// Команды и основные сообщения
-	MessageStart = `🤖 Добро пожаловать!
-
-Я помогу вам проанализировать экспорты чатов из Telegram и извлечь участников.
-
-Как использовать:
-1. /upload - загрузить файлы (до 10 шт.)
-2. /process - начать анализ
-3. /help - помощь
-4. /cancel - отменить текущую операцию
-
-Типы файлов:
-• JSON (основной формат Telegram)
-• HTML (частичная поддержка)
-
-Результат:
-• До 50 участников → список в чат
-• От 51+ участников → Excel файл`
-
-	MessageHelp = `ℹ️ Справка
-
-/upload - загрузить файл экспорта (до 10 файлов)
-/process - обработать загруженные файлы и получить результат
-/cancel - отменить операцию и очистить загруженные файлы
-/start - главное меню
-
-Как экспортировать чат из Telegram:
-1. Откройте чат/группу
-2. Нажмите на название чата (вверху)
-3. Меню → Экспорт истории чата
-4. Выберите формат (рекомендуется JSON)
-5. Дождитесь готовности файла
-6. Отправьте файл этому боту
-
-Ограничения:
-• Максимум файлов: 10
-• Максимум размер файла: 10 МБ
-• Максимум в сессии: 100 МБ
-• Время сессии: 1 час`
-
-	MessageWelcomeBack = `Добро пожаловать обратно! 👋
-
-У вас есть загруженные файлы. Отправьте:
-• /upload - добавить ещё файлы
-• /process - начать анализ текущих файлов
-• /cancel - отменить и начать заново`
+	MessageStart       MessageID = "start"
+	MessageHelp        MessageID = "help"
+	MessageWelcomeBack MessageID = "welcome_back"
 
 	// Загрузка файлов
-	MessageFileReceived = `✅ Файл '%s' успешно загружен`
-
-	MessageFileLimitExceeded = `❌ Лимит файлов превышен!
-
-Вы можете загрузить максимум 10 файлов.
-Загружено: %d файлов
-
-Отправьте /process для обработки или /cancel для отмены.`
-
-	MessageFileSizeExceeded = `❌ Файл слишком большой!
-
-Максимальный размер одного файла: 10 МБ
-Размер вашего файла: %.1f МБ`
-
-	MessageSessionSizeLimitExceeded = `❌ Общий размер файлов слишком большой!
-
-Максимум в сессии: 100 МБ
-Текущий размер: %.1f МБ
-
-Отправьте /process для обработки или загрузите меньше файлов.`
-
-	MessageFilesReady = `📦 Готово к обработке!
-
-Загружено файлов: %d
-Общий размер: %.1f МБ
-
-Отправьте /process для анализа или /upload для добавления ещё файлов.`
+	MessageFileReceived      MessageID = "file_received"
+	MessageFileLimitExceeded MessageID = "file_limit_exceeded"
+	MessageFileSizeExceeded  MessageID = "file_size_exceeded"
+	MessageTotalSizeExceeded MessageID = "total_size_exceeded"
+	MessageFilesReady        MessageID = "files_ready"
 
 	// Обработка
-	MessageProcessing = `⏳ Обработка началась...
-
-Анализирую %d файлов...`
-
-	MessageProcessingError = `❌ Ошибка при обработке файлов!
-
-Детали: %s
-
-Пожалуйста, проверьте файлы и попробуйте снова.`
-
-	MessageFileParseError = `❌ Ошибка при анализе файла '%s'!
-
-Возможно, файл повреждён или имеет неправильный формат.
-Пожалуйста, экспортируйте чат заново и попробуйте ещё раз.
-
-Детали: %s`
+	MessageProcessing      MessageID = "processing"
+	MessageProcessingError MessageID = "processing_error"
+	MessageFileParseError  MessageID = "file_parse_error"
 
 	// Результаты
-	MessageResultReady = `✅ Анализ завершён!
-
-📊 Статистика:
-• Всего участников: %d
-• Упоминаний найдено: %d
-• Каналов найдено: %d
-
-Обработано событий: %d`
-
-	MessageNoParticipants = `⚠️ Анализ завершён, но не найдено участников с username.
-
-Вероятно, чат содержит только служебные сообщения или участники без username.
-Проверьте экспортированный файл и попробуйте снова.`
-
-	MessageExcelReady = `📄 Результаты готовы в формате Excel!
-
-Файл содержит листы:
-• Participants - основные участники
-• Mentions - упомянутые пользователи
-• Channels - найденные каналы
-
-Скачайте файл ниже 👇`
-
-	MessageListReady = `📝 Результаты готовы! Вот список участников:
-
-%s`
-
-	MessageListTruncated = `📝 Результаты готовы! Вот список участников (сообщение %d/%d):
-
-%s`
+	MessageResultReady         MessageID = "result_ready"
+	MessageActiveFilter        MessageID = "active_filter"
+	MessageNoEventsForFilter   MessageID = "no_events_for_filter"
+	MessageNoParticipants      MessageID = "no_participants"
+	MessageExcelReady          MessageID = "excel_ready"
+	MessageHTMLReady           MessageID = "html_ready"
+	MessageInteractionsSummary MessageID = "interactions_summary"
+	MessageInteractionsNone    MessageID = "interactions_none"
+	MessageContentSummary      MessageID = "content_summary"
+	MessageGraphReady          MessageID = "graph_ready"
+	MessageListReady           MessageID = "list_ready"
+	MessageListTruncated       MessageID = "list_truncated"
+
+	// Статистика активности
+	MessageStats     MessageID = "stats"
+	MessageStatsWeek MessageID = "stats_week"
+
+	MessageWeekdays MessageID = "weekdays"
+
+	// Подписи графиков и листов активности
+	MessageLabelDate        MessageID = "label_date"
+	MessageLabelWeekStart   MessageID = "label_week_start"
+	MessageLabelDayHour     MessageID = "label_day_hour"
+	MessageLabelMessages    MessageID = "label_messages"
+	MessageLabelActiveUsers MessageID = "label_active_users"
+	MessageLabelDaily       MessageID = "label_daily"
+	MessageLabelWeekly      MessageID = "label_weekly"
+	MessageLabelHours       MessageID = "label_hours"
+	MessageLabelTopAuthors  MessageID = "label_top_authors"
+
+	// Описание фильтра
+	MessageFilterNone        MessageID = "filter_none"
+	MessageFilterFrom        MessageID = "filter_from"
+	MessageFilterTo          MessageID = "filter_to"
+	MessageFilterLastDays    MessageID = "filter_last_days"
+	MessageFilterService     MessageID = "filter_service"
+	MessageFilterOnlyText    MessageID = "filter_only_text"
+	MessageFilterOnlyReplies MessageID = "filter_only_replies"
+
+	// Типы аккаунтов
+	MessageKindUser           MessageID = "kind_user"
+	MessageKindBot            MessageID = "kind_bot"
+	MessageKindChannel        MessageID = "kind_channel"
+	MessageKindAnonymousAdmin MessageID = "kind_anonymous_admin"
+
+	// Графики
+	MessageChartsReady MessageID = "charts_ready"
+	MessageChartsUsage MessageID = "charts_usage"
+	MessageChartsOn    MessageID = "charts_on"
+	MessageChartsOff   MessageID = "charts_off"
+
+	// Настройки
+	MessageBotsHidden      MessageID = "bots_hidden"
+	MessageBotsShown       MessageID = "bots_shown"
+	MessageBotsUsage       MessageID = "bots_usage"
+	MessageBotsStateHidden MessageID = "bots_state_hidden"
+	MessageBotsStateShown  MessageID = "bots_state_shown"
+	MessageFilterUsage     MessageID = "filter_usage"
+	MessageFilterSet       MessageID = "filter_set"
+	MessageFilterReset     MessageID = "filter_reset"
+	MessageFilterInvalid   MessageID = "filter_invalid"
+	MessageSettingsUsage   MessageID = "settings_usage"
+	MessageSettingsState   MessageID = "settings_state"
+	MessageSettingsUpdated MessageID = "settings_updated"
+	MessageSettingsInvalid MessageID = "settings_invalid"
+	MessageSwitchOn        MessageID = "switch_on"
+	MessageSwitchOff       MessageID = "switch_off"
+
+	// Контакты
+	MessageContactsUsage    MessageID = "contacts_usage"
+	MessageContactsOn       MessageID = "contacts_on"
+	MessageContactsOff      MessageID = "contacts_off"
+	MessageContactsDisabled MessageID = "contacts_disabled"
+	MessageContactsExported MessageID = "contacts_exported"
+
+	// Списки игнорирования
+	MessageIgnoreUsage        MessageID = "ignore_usage"
+	MessageIgnoreAdded        MessageID = "ignore_added"
+	MessageAllowAdded         MessageID = "allow_added"
+	MessageAllowWithoutIgnore MessageID = "allow_without_ignore"
+	MessageIgnoreRemoved      MessageID = "ignore_removed"
+	MessageIgnoreNotFound     MessageID = "ignore_not_found"
+	MessageIgnoreInvalid      MessageID = "ignore_invalid"
+	MessageIgnoreListEmpty    MessageID = "ignore_list_empty"
+	MessageIgnoreList         MessageID = "ignore_list"
+
+	// Язык
+	MessageLangUsage    MessageID = "lang_usage"
+	MessageLangSet      MessageID = "lang_set"
+	MessageLangAuto     MessageID = "lang_auto"
+	MessageLangNameRU   MessageID = "lang_name_ru"
+	MessageLangNameEN   MessageID = "lang_name_en"
+	MessageLangNameAuto MessageID = "lang_name_auto"
+
+	// Склонения
+	MessagePluralFiles    MessageID = "plural_files"
+	MessagePluralMessages MessageID = "plural_messages"
+	MessagePluralAuthors  MessageID = "plural_authors"
+	MessagePluralMinutes  MessageID = "plural_minutes"
+	MessagePluralHours    MessageID = "plural_hours"
 
 	// Отмена
-	MessageCancelled = `❌ Операция отменена.
-
-Все загруженные файлы удалены.
-Используйте /start для начала заново.`
-
-	MessageNothingToCancel = `ℹ️ Нечего отменять.
-
-Используйте /upload для загрузки файлов.`
+	MessageCancelled       MessageID = "cancelled"
+	MessageNothingToCancel MessageID = "nothing_to_cancel"
+
+	// Доступ
+	MessageAccessDenied      MessageID = "access_denied"
+	MessageAccessChatDenied  MessageID = "access_chat_denied"
+	MessageAccessPrivateOnly MessageID = "access_private_only"
+
+	// Квоты
+	MessageQuotaRate    MessageID = "quota_rate"
+	MessageQuotaProcess MessageID = "quota_process"
+	MessageQuotaUpload  MessageID = "quota_upload"
+	MessageQuotaBanned  MessageID = "quota_banned"
+
+	// Администрирование
+	MessageAdminHelp           MessageID = "admin_help"
+	MessageAdminStats          MessageID = "admin_stats"
+	MessageAdminUnknown        MessageID = "admin_unknown"
+	MessageAdminSessions       MessageID = "admin_sessions"
+	MessageAdminSessionLine    MessageID = "admin_session_line"
+	MessageAdminSessionsMore   MessageID = "admin_sessions_more"
+	MessageAdminNoSessions     MessageID = "admin_no_sessions"
+	MessageAdminKillUsage      MessageID = "admin_kill_usage"
+	MessageAdminKillNotFound   MessageID = "admin_kill_not_found"
+	MessageAdminKilled         MessageID = "admin_killed"
+	MessageAdminSessionReset   MessageID = "admin_session_reset"
+	MessageAdminBroadcastUsage MessageID = "admin_broadcast_usage"
+	MessageAdminBroadcast      MessageID = "admin_broadcast"
+	MessageAdminBroadcastDone  MessageID = "admin_broadcast_done"
+	MessageAdminLogLevelUsage  MessageID = "admin_loglevel_usage"
+	MessageAdminLogLevelSet    MessageID = "admin_loglevel_set"
+	MessageAdminBans           MessageID = "admin_bans"
+	MessageAdminBanLine        MessageID = "admin_ban_line"
+	MessageAdminNoBans         MessageID = "admin_no_bans"
+	MessageAdminUnbanUsage     MessageID = "admin_unban_usage"
+	MessageAdminUnbanned       MessageID = "admin_unbanned"
+	MessageAdminUnbanNotFound  MessageID = "admin_unban_not_found"
 
 	// Ошибки
-	MessageUnexpectedError = `❌ Неожиданная ошибка!
-
-Пожалуйста, попробуйте снова или свяжитесь с поддержкой.`
-
-	MessageSessionExpired = `⏰ Ваша сессия истекла.
-
-Используйте /start для начала заново.`
-
-	MessageNoFiles = `📭 Нет загруженных файлов!
-
-Используйте /upload для загрузки файлов чата.`
+	MessageUnknownCommand  MessageID = "unknown_command"
+	MessageFileUnreadable  MessageID = "file_unreadable"
+	MessageUnexpectedError MessageID = "unexpected_error"
+	MessageNoFiles         MessageID = "no_files"
 
 	// Загрузка
-	MessageUploadPrompt = `📤 Отправьте файлы для анализа
-
-Поддерживаемые форматы: JSON, HTML
-Максимум файлов за раз: 10
-Максимум размер одного файла: 10 МБ
-
-Вы можете отправить несколько файлов одним сообщением, или отправляйте их по одному.
-
-Когда закончите загружать файлы, отправьте /process`
-
-	MessageWaitingForFiles = `⏳ Ожидаю файлов...
-
-Отправьте файлы или нажмите:
-• /process - если файлы готовы
-• /cancel - для отмены`
+	MessageUploadPrompt MessageID = "upload_prompt"
 )
@@ -0,0 +1,524 @@
+package telegram
+
+// messagesEN сообщения бота на английском языке
+var messagesEN = map[MessageID]string{
+	// Команды и основные сообщения
+	MessageStart: `🤖 Welcome!
+
+I will help you analyze Telegram chat exports and extract the participants.
+
+How to use:
+1. /upload - upload files (up to %d)
+2. /process - start the analysis
+3. /help - help
+4. /cancel - cancel the current operation
+5. /bots - show or hide bots
+6. /filter - limit the period and message types
+7. /settings - analysis settings
+8. /ignore - always exclude a participant from the result
+9. /stats - chat activity over time
+10. /lang - message language (Язык)
+
+File types:
+• JSON (main Telegram format)
+• HTML (partial support)
+
+Result:
+• Up to %d participants → list in the chat
+• %d+ participants → Excel file`,
+
+	MessageHelp: `ℹ️ Help
+
+/upload - upload an export file (up to %d %s)
+/process - process the uploaded files and get the result
+/cancel - cancel the operation and delete the uploaded files
+/bots hide | show - hide or show bots in the result
+/filter - filter by dates and message types (/filter reset - clear)
+/settings - analysis settings (minimum messages, mentions, case)
+/ignore @name | id:123 | /regexp/ - always exclude a participant
+/unignore - remove a rule from the ignore list
+/allow, /unallow - exceptions from the ignore list
+/ignorelist - show the lists
+/contacts on | off - extract phones and emails (off by default)
+/stats - chat activity by day, week and hour
+/charts on | off - send charts after processing (on by default)
+/lang ru | en - message language (/lang auto - as in Telegram)
+/start - main menu
+
+How to export a chat from Telegram:
+1. Open the chat/group
+2. Tap the chat name (at the top)
+3. Menu → Export chat history
+4. Choose the format (JSON recommended)
+5. Wait until the file is ready
+6. Send the file to this bot
+
+Limits:
+• Max files: %d
+• Max file size: %d MB
+• Max per session: %d MB
+• Session lifetime: %s`,
+
+	MessageWelcomeBack: `Welcome back! 👋
+
+You have uploaded files. Send:
+• /upload - add more files
+• /process - analyze the current files
+• /cancel - cancel and start over`,
+
+	// Загрузка файлов
+	MessageFileReceived: `✅ File '%s' uploaded`,
+
+	MessageFileLimitExceeded: `❌ File limit exceeded!
+
+You can upload at most %d %s.
+Uploaded: %d %s
+
+Send /process to analyze them or /cancel to cancel.`,
+
+	MessageFileSizeExceeded: `❌ The file is too large!
+
+Max size of a single file: %d MB
+Your file: %.1f MB`,
+
+	MessageTotalSizeExceeded: `❌ Total file size limit exceeded!
+
+Max total size: %d MB
+Already uploaded: %.1f MB
+Your file: %.1f MB
+
+Send /process to analyze them or /cancel to cancel.`,
+
+	MessageFilesReady: `📦 Ready to process!
+
+Files uploaded: %d
+Total size: %.1f MB
+
+Send /process to analyze or /upload to add more files.`,
+
+	// Обработка
+	MessageProcessing: `⏳ Processing started...
+
+Analyzing %d %s...`,
+
+	MessageProcessingError: `❌ Failed to process the files!
+
+Details: %s
+
+Please check the files and try again.`,
+
+	MessageFileParseError: `❌ Failed to parse file '%s'!
+
+The file may be corrupted or in a wrong format.
+Please export the chat again and retry.
+
+Details: %s`,
+
+	// Результаты
+	MessageResultReady: `✅ Analysis complete!
+
+📊 Statistics:
+• Participants: %d
+• Bots among them: %d
+• Mentions found: %d
+• Channels found: %d
+
+Events processed: %d`,
+
+	MessageActiveFilter: `
+🔎 Filter: %s`,
+
+	MessageNoEventsForFilter: `⚠️ No messages match the filter.
+
+🔎 Filter: %s
+
+Change the filter with /filter or clear it: /filter reset`,
+
+	MessageNoParticipants: `⚠️ Analysis complete, but no participants with a username were found.
+
+The chat probably contains only service messages or participants without usernames.
+Check the exported file and try again.`,
+
+	MessageExcelReady: `📄 The results are ready as an Excel file!
+
+Sheets:
+• Participants - message authors
+• Mentions - mentioned users
+• Channels - channels found
+• Interactions, Forward sources - replies and forwards (if any)
+• Hashtags, Links, Domains - hashtags, links and domains (if any)
+• Activity by day, Activity by week, Heatmap - activity over time with charts
+
+Download the file below 👇`,
+
+	MessageHTMLReady: `🌐 Browser report: searchable and sortable tables, statistics and charts. Works offline.`,
+
+	MessageInteractionsSummary: `🔁 Interactions in the chat
+
+Who replies to whom most often:
+%s
+
+Most forwarded sources:
+%s`,
+
+	MessageInteractionsNone: `• none found`,
+
+	MessageContentSummary: `#️⃣ Hashtags and links
+
+Hashtags: %d
+%s
+
+Links: %d, domains: %d
+%s
+
+Full tables are in the CSV files below 👇`,
+
+	MessageGraphReady: `🕸 Reply and forward graph (GraphML / DOT)`,
+
+	MessageListReady: `📝 The results are ready! Here is the participant list:
+
+%s`,
+
+	MessageListTruncated: `📝 The results are ready! Here is the participant list (message %d/%d):
+
+%s`,
+
+	// Статистика активности
+	MessageStats: `📈 Chat activity
+
+Period: %s - %s
+Messages: %d, authors: %d
+Average per day: %.1f
+Busiest day: %s (%d %s)
+Peak time: %s, %02d:00-%02d:00 (%d %s)
+
+Recent weeks:
+%s
+
+Charts and the heatmap are in the Excel file after /process`,
+
+	MessageStatsWeek: `• from %s: %d %s, %d %s`,
+
+	MessageWeekdays: "Mon|Tue|Wed|Thu|Fri|Sat|Sun",
+
+	// Подписи графиков и листов активности
+	MessageLabelDate:        "Date",
+	MessageLabelWeekStart:   "Week of",
+	MessageLabelDayHour:     "Day / hour",
+	MessageLabelMessages:    "Messages",
+	MessageLabelActiveUsers: "Active members",
+	MessageLabelDaily:       "Messages per day",
+	MessageLabelWeekly:      "Messages per week",
+	MessageLabelHours:       "Activity by hour",
+	MessageLabelTopAuthors:  "Most active members",
+
+	// Описание фильтра
+	MessageFilterNone:        "no filter",
+	MessageFilterFrom:        "from %s",
+	MessageFilterTo:          "to %s",
+	MessageFilterLastDays:    "last %d days",
+	MessageFilterService:     "with service messages",
+	MessageFilterOnlyText:    "text only",
+	MessageFilterOnlyReplies: "replies only",
+
+	// Типы аккаунтов
+	MessageKindUser:           "user",
+	MessageKindBot:            "bot",
+	MessageKindChannel:        "channel",
+	MessageKindAnonymousAdmin: "anonymous admin",
+
+	// Графики
+	MessageChartsReady: `📊 Chat activity charts`,
+
+	MessageChartsUsage: `📊 Charts after processing: %s
+
+• /charts on - send charts
+• /charts off - don't send`,
+
+	MessageChartsOn: `✅ Charts will be sent after the next /process`,
+
+	MessageChartsOff: `✅ Charts are off. Turn them back on: /charts on`,
+
+	// Настройки
+	MessageBotsHidden: `🤖 Bots will be excluded from the result.
+
+To show bots again, send /bots show`,
+
+	MessageBotsShown: `🤖 Bots will be included in the result and marked in the list.
+
+To hide bots, send /bots hide`,
+
+	MessageBotsUsage: `ℹ️ Usage: /bots hide or /bots show
+
+Bots are currently: %s`,
+
+	MessageBotsStateHidden: "hidden",
+	MessageBotsStateShown:  "shown",
+
+	MessageFilterUsage: `🔎 Current filter: %s
+
+Options (can be combined):
+• from=2024-01-01 - messages from the date
+• to=2024-03-31 - messages up to the date inclusive
+• last=30 - only the last 30 days
+• service - count service messages (joins, pins)
+• text - only messages with text
+• replies - only replies
+
+Example: /filter last=30 text
+Clear: /filter reset`,
+
+	MessageFilterSet: `✅ Filter set: %s
+
+It will be applied on the next /process`,
+
+	MessageFilterReset: `✅ Filter cleared, all messages will be counted.`,
+
+	MessageFilterInvalid: `❌ Could not parse the filter: %s
+
+Send /filter to see the available options.`,
+
+	MessageSettingsUsage: `⚙️ Analysis settings
+
+%s
+
+Change (several at once is fine):
+• min=3 - count authors with at least 3 messages
+• mentions=on|off - collect mentions
+• case=on|off - case-sensitive names
+• channels=on|off - count channels and anonymous admins as participants
+
+Example: /settings min=2 mentions=off
+Restore defaults: /settings reset`,
+
+	MessageSettingsState: `• Minimum messages per author: %d
+• Mentions: %s
+• Case-sensitive: %s
+• Channels and anonymous admins: %s`,
+
+	MessageSettingsUpdated: `✅ Settings updated
+
+%s`,
+
+	MessageSettingsInvalid: `❌ Unknown or invalid option: %s
+
+Send /settings to see the available options.`,
+
+	MessageSwitchOn:  "on",
+	MessageSwitchOff: "off",
+
+	// Контакты
+	MessageContactsUsage: `📇 Phone and email extraction: %s
+
+⚠️ Participants' contacts are personal data. Only turn extraction on if you have a lawful basis to process them.
+
+• /contacts on - enable for the current session
+• /contacts off - disable`,
+
+	MessageContactsOn: `⚠️ Contact extraction is on.
+
+On the next run the bot will find phones and emails posted by participants and attach them as a Contacts sheet and a contacts.csv file.
+The bot neither stores nor logs the contacts found. Turn off: /contacts off`,
+
+	MessageContactsOff: `✅ Contact extraction is off.`,
+
+	MessageContactsDisabled: `🔒 Contact extraction is disabled by the bot administrator.`,
+
+	MessageContactsExported: `⚠️ Contacts found: %d
+
+The contacts.csv file and the Contacts sheet contain participants' personal data. Don't share them with third parties and delete them once no longer needed.`,
+
+	// Списки игнорирования
+	MessageIgnoreUsage: `ℹ️ List participants separated by spaces:
+
+• @username - by username
+• id:123456 - by account ID
+• /regexp/ - by regular expression
+
+Example: /ignore @spambot id:123456 /^promo/
+
+/allow rules take precedence over /ignore.`,
+
+	MessageIgnoreAdded: `🚫 Added to the ignore list: <code>%s</code>
+
+The lists are kept between sessions. View: /ignorelist`,
+
+	MessageAllowAdded: `✅ Added to the exceptions: <code>%s</code>
+
+These participants won't be hidden by /ignore rules.`,
+
+	MessageAllowWithoutIgnore: `⚠️ Added to the exceptions: <code>%s</code>
+
+The ignore list is empty, so exceptions have no effect yet: they only cancel /ignore rules and don't limit the result to allowed participants.`,
+
+	MessageIgnoreRemoved: `✅ Removed from the list: <code>%s</code>`,
+
+	MessageIgnoreNotFound: `⚠️ Not in the list: <code>%s</code>
+
+View the current rules: /ignorelist`,
+
+	MessageIgnoreInvalid: `❌ Invalid rule: <code>%s</code>
+
+Send /ignore without arguments to see the format.`,
+
+	MessageIgnoreListEmpty: `📭 The ignore lists are empty.
+
+Add a participant: /ignore @username`,
+
+	MessageIgnoreList: `🚫 Ignored:
+%s
+
+✅ Exceptions (allow):
+%s
+
+Exceptions only cancel ignore rules: a participant in both lists stays in the result. Everyone else is shown as usual.`,
+
+	// Язык
+	MessageLangUsage: `🌐 Message language: %s
+
+• /lang ru - Русский
+• /lang en - English
+• /lang auto - as in your Telegram settings`,
+
+	MessageLangSet: `✅ Message language: English`,
+
+	MessageLangAuto: `✅ The message language will follow your Telegram settings.`,
+
+	MessageLangNameRU:   "Русский",
+	MessageLangNameEN:   "English",
+	MessageLangNameAuto: "as in Telegram (%s)",
+
+	// Склонения: формы для 1 и остальных чисел
+	MessagePluralFiles:    "file|files",
+	MessagePluralMessages: "message|messages",
+	MessagePluralAuthors:  "author|authors",
+	MessagePluralMinutes:  "minute|minutes",
+	MessagePluralHours:    "hour|hours",
+
+	// Отмена
+	MessageCancelled: `❌ Operation cancelled.
+
+All uploaded files have been deleted.
+Use /start to begin again.`,
+
+	MessageNothingToCancel: `ℹ️ Nothing to cancel.
+
+Use /upload to upload files.`,
+
+	// Доступ
+	MessageAccessDenied: `🔒 You don't have access to this bot.
+
+To request access, send your ID to the bot administrator: <code>%d</code>`,
+
+	MessageAccessChatDenied: `🔒 The bot is not enabled in this chat.
+
+Message the bot privately or ask the administrator to add chat <code>%d</code>.`,
+
+	MessageAccessPrivateOnly: `🔒 The bot works in private chats only. Please message it directly.`,
+
+	// Квоты
+	MessageQuotaRate: `⏳ Too many commands in a row. Please wait %s.`,
+
+	MessageQuotaProcess: `⏳ Processing limit reached. The next run will be available in %s.`,
+
+	MessageQuotaUpload: `⏳ Daily upload limit (%d MB) reached. You can send the file again in %s.`,
+
+	MessageQuotaBanned: `🚫 Access is temporarily restricted after repeated limit violations. Try again in %s.`,
+
+	// Администрирование
+	MessageAdminHelp: `🛠 <b>Admin commands</b>
+
+/admin_stats – bot status: sessions, queues, disk, uptime
+/admin_sessions – active sessions
+/admin_kill &lt;user or session ID&gt; – reset a session and delete its files
+/admin_broadcast &lt;text&gt; – notify all users with active sessions
+/admin_loglevel [debug|info|warn|error] – log level
+/admin_bans – temporary bans
+/admin_unban &lt;user ID&gt; – lift a ban`,
+
+	MessageAdminStats: `🛠 <b>Bot status</b>
+
+Uptime: %s
+Active sessions: %d (files: %d)
+Temporary bans: %d
+Updates in progress: %d
+Messages queued for sending: %d
+TEMP_DIR: %s used, %s free
+Log level: %s`,
+
+	MessageAdminUnknown: `n/a`,
+
+	MessageAdminSessions: `👥 <b>Sessions: %d</b>
+
+%s`,
+
+	MessageAdminSessionLine: `<code>%s</code> · user <code>%d</code> · %s · files: %d · age %s · idle %s`,
+
+	MessageAdminSessionsMore: `… and %d more`,
+
+	MessageAdminNoSessions: `👥 No active sessions.`,
+
+	MessageAdminKillUsage: `Usage: /admin_kill &lt;user or session ID&gt;
+
+See /admin_sessions for IDs.`,
+
+	MessageAdminKillNotFound: `❌ Session <code>%s</code> not found.`,
+
+	MessageAdminKilled: `✅ Session <code>%s</code> reset, files deleted: %d.`,
+
+	MessageAdminSessionReset: `ℹ️ Your session was reset by the administrator and the uploaded files were deleted.
+
+Use /upload to start again.`,
+
+	MessageAdminBroadcastUsage: `Usage: /admin_broadcast &lt;notice text&gt;`,
+
+	MessageAdminBroadcast: `📢 %s`,
+
+	MessageAdminBroadcastDone: `✅ Notice delivered: %d of %d.`,
+
+	MessageAdminLogLevelUsage: `Current log level: <b>%s</b>
+
+Usage: /admin_loglevel debug|info|warn|error`,
+
+	MessageAdminLogLevelSet: `✅ Log level: <b>%s</b>`,
+
+	MessageAdminBans: `🚫 <b>Temporary bans: %d</b>
+
+%s`,
+
+	MessageAdminBanLine: `user <code>%d</code> · until %s (%s left)`,
+
+	MessageAdminNoBans: `🚫 No temporary bans.`,
+
+	MessageAdminUnbanUsage: `Usage: /admin_unban &lt;user ID&gt;
+
+See /admin_bans for IDs.`,
+
+	MessageAdminUnbanned: `✅ User <code>%d</code> unbanned.`,
+
+	MessageAdminUnbanNotFound: `❌ User <code>%s</code> is not banned.`,
+
+	// Ошибки
+	MessageUnknownCommand: `❓ Unknown command. See /help for the list of commands.`,
+
+	MessageFileUnreadable: `unable to read the file`,
+
+	MessageUnexpectedError: `❌ Unexpected error!
+
+Please try again or contact support.`,
+
+	MessageNoFiles: `📭 No files uploaded!
+
+Use /upload to upload chat files.`,
+
+	// Загрузка
+	MessageUploadPrompt: `📤 Send files for analysis
+
+Supported formats: JSON, HTML
+Max files at once: %d
+Max size of a single file: %d MB
+
+You can send several files in one message or one by one.
+
+When you are done uploading, send /process`,
+}
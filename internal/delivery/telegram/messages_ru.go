@@ -0,0 +1,524 @@
+package telegram
+
+// messagesRU сообщения бота на русском языке
+var messagesRU = map[MessageID]string{
+	// Команды и основные сообщения
+	MessageStart: `🤖 Добро пожаловать!
+
+Я помогу вам проанализировать экспорты чатов из Telegram и извлечь участников.
+
+Как использовать:
+1. /upload - загрузить файлы (до %d шт.)
+2. /process - начать анализ
+3. /help - помощь
+4. /cancel - отменить текущую операцию
+5. /bots - показывать или скрывать ботов
+6. /filter - ограничить период и типы сообщений
+7. /settings - параметры анализа
+8. /ignore - всегда исключать участника из результата
+9. /stats - активность чата по времени
+10. /lang - язык сообщений (Language)
+
+Типы файлов:
+• JSON (основной формат Telegram)
+• HTML (частичная поддержка)
+
+Результат:
+• До %d участников → список в чат
+• От %d участников → Excel файл`,
+
+	MessageHelp: `ℹ️ Справка
+
+/upload - загрузить файл экспорта (до %d %s)
+/process - обработать загруженные файлы и получить результат
+/cancel - отменить операцию и очистить загруженные файлы
+/bots hide | show - скрыть или показать ботов в результате
+/filter - фильтр по датам и типам сообщений (/filter reset - сбросить)
+/settings - параметры анализа (минимум сообщений, упоминания, регистр)
+/ignore @name | id:123 | /regexp/ - всегда исключать участника
+/unignore - убрать правило из списка игнорирования
+/allow, /unallow - исключения из списка игнорирования
+/ignorelist - показать списки
+/contacts on | off - извлекать телефоны и email (выключено по умолчанию)
+/stats - активность чата по дням, неделям и часам
+/charts on | off - присылать графики после обработки (включено по умолчанию)
+/lang ru | en - язык сообщений (/lang auto - как в Telegram)
+/start - главное меню
+
+Как экспортировать чат из Telegram:
+1. Откройте чат/группу
+2. Нажмите на название чата (вверху)
+3. Меню → Экспорт истории чата
+4. Выберите формат (рекомендуется JSON)
+5. Дождитесь готовности файла
+6. Отправьте файл этому боту
+
+Ограничения:
+• Максимум файлов: %d
+• Максимум размер файла: %d МБ
+• Максимум в сессии: %d МБ
+• Время сессии: %s`,
+
+	MessageWelcomeBack: `Добро пожаловать обратно! 👋
+
+У вас есть загруженные файлы. Отправьте:
+• /upload - добавить ещё файлы
+• /process - начать анализ текущих файлов
+• /cancel - отменить и начать заново`,
+
+	// Загрузка файлов
+	MessageFileReceived: `✅ Файл '%s' успешно загружен`,
+
+	MessageFileLimitExceeded: `❌ Лимит файлов превышен!
+
+Вы можете загрузить максимум %d %s.
+Загружено: %d %s
+
+Отправьте /process для обработки или /cancel для отмены.`,
+
+	MessageFileSizeExceeded: `❌ Файл слишком большой!
+
+Максимальный размер одного файла: %d МБ
+Размер вашего файла: %.1f МБ`,
+
+	MessageTotalSizeExceeded: `❌ Превышен общий размер файлов!
+
+Максимальный общий размер: %d МБ
+Уже загружено: %.1f МБ
+Размер вашего файла: %.1f МБ
+
+Отправьте /process для обработки или /cancel для отмены.`,
+
+	MessageFilesReady: `📦 Готово к обработке!
+
+Загружено файлов: %d
+Общий размер: %.1f МБ
+
+Отправьте /process для анализа или /upload для добавления ещё файлов.`,
+
+	// Обработка
+	MessageProcessing: `⏳ Обработка началась...
+
+Анализирую %d %s...`,
+
+	MessageProcessingError: `❌ Ошибка при обработке файлов!
+
+Детали: %s
+
+Пожалуйста, проверьте файлы и попробуйте снова.`,
+
+	MessageFileParseError: `❌ Ошибка при анализе файла '%s'!
+
+Возможно, файл повреждён или имеет неправильный формат.
+Пожалуйста, экспортируйте чат заново и попробуйте ещё раз.
+
+Детали: %s`,
+
+	// Результаты
+	MessageResultReady: `✅ Анализ завершён!
+
+📊 Статистика:
+• Всего участников: %d
+• Из них ботов: %d
+• Упоминаний найдено: %d
+• Каналов найдено: %d
+
+Обработано событий: %d`,
+
+	MessageActiveFilter: `
+🔎 Фильтр: %s`,
+
+	MessageNoEventsForFilter: `⚠️ Ни одно сообщение не подходит под фильтр.
+
+🔎 Фильтр: %s
+
+Измените фильтр командой /filter или сбросьте его: /filter reset`,
+
+	MessageNoParticipants: `⚠️ Анализ завершён, но не найдено участников с username.
+
+Вероятно, чат содержит только служебные сообщения или участники без username.
+Проверьте экспортированный файл и попробуйте снова.`,
+
+	MessageExcelReady: `📄 Результаты готовы в формате Excel!
+
+Файл содержит листы:
+• Participants - основные участники
+• Mentions - упомянутые пользователи
+• Channels - найденные каналы
+• Interactions, Forward sources - ответы и пересылки (если есть)
+• Hashtags, Links, Domains - хэштеги, ссылки и домены (если есть)
+• Activity by day, Activity by week, Heatmap - активность по времени с графиками
+
+Скачайте файл ниже 👇`,
+
+	MessageHTMLReady: `🌐 Отчёт для браузера: таблицы с поиском и сортировкой, статистика и графики. Открывается без интернета.`,
+
+	MessageInteractionsSummary: `🔁 Взаимодействия в чате
+
+Кто кому отвечает чаще всего:
+%s
+
+Самые пересылаемые источники:
+%s`,
+
+	MessageInteractionsNone: `• не найдено`,
+
+	MessageContentSummary: `#️⃣ Хэштеги и ссылки
+
+Хэштегов: %d
+%s
+
+Ссылок: %d, доменов: %d
+%s
+
+Полные таблицы - в CSV файлах ниже 👇`,
+
+	MessageGraphReady: `🕸 Граф ответов и пересылок (GraphML / DOT)`,
+
+	MessageListReady: `📝 Результаты готовы! Вот список участников:
+
+%s`,
+
+	MessageListTruncated: `📝 Результаты готовы! Вот список участников (сообщение %d/%d):
+
+%s`,
+
+	// Статистика активности
+	MessageStats: `📈 Активность чата
+
+Период: %s - %s
+Сообщений: %d, авторов: %d
+В среднем за день: %.1f
+Самый активный день: %s (%d %s)
+Пиковое время: %s, %02d:00-%02d:00 (%d %s)
+
+Последние недели:
+%s
+
+Графики и тепловая карта - в Excel файле после /process`,
+
+	MessageStatsWeek: `• с %s: %d %s, %d %s`,
+
+	MessageWeekdays: "Пн|Вт|Ср|Чт|Пт|Сб|Вс",
+
+	// Подписи графиков и листов активности
+	MessageLabelDate:        "Дата",
+	MessageLabelWeekStart:   "Неделя с",
+	MessageLabelDayHour:     "День / час",
+	MessageLabelMessages:    "Сообщений",
+	MessageLabelActiveUsers: "Активных участников",
+	MessageLabelDaily:       "Сообщения по дням",
+	MessageLabelWeekly:      "Сообщения по неделям",
+	MessageLabelHours:       "Активность по часам",
+	MessageLabelTopAuthors:  "Самые активные участники",
+
+	// Описание фильтра
+	MessageFilterNone:        "без фильтра",
+	MessageFilterFrom:        "с %s",
+	MessageFilterTo:          "по %s",
+	MessageFilterLastDays:    "последние %d дн.",
+	MessageFilterService:     "со служебными сообщениями",
+	MessageFilterOnlyText:    "только с текстом",
+	MessageFilterOnlyReplies: "только ответы",
+
+	// Типы аккаунтов
+	MessageKindUser:           "пользователь",
+	MessageKindBot:            "бот",
+	MessageKindChannel:        "канал",
+	MessageKindAnonymousAdmin: "анонимный админ",
+
+	// Графики
+	MessageChartsReady: `📊 Графики активности чата`,
+
+	MessageChartsUsage: `📊 Графики после обработки: %s
+
+• /charts on - присылать графики
+• /charts off - не присылать`,
+
+	MessageChartsOn: `✅ Графики будут отправлены после следующей обработки /process`,
+
+	MessageChartsOff: `✅ Графики отключены. Включить снова: /charts on`,
+
+	// Настройки
+	MessageBotsHidden: `🤖 Боты будут исключены из результата.
+
+Чтобы снова показывать ботов, отправьте /bots show`,
+
+	MessageBotsShown: `🤖 Боты будут включены в результат и помечены в списке.
+
+Чтобы скрыть ботов, отправьте /bots hide`,
+
+	MessageBotsUsage: `ℹ️ Использование: /bots hide или /bots show
+
+Сейчас боты: %s`,
+
+	MessageBotsStateHidden: "скрыты",
+	MessageBotsStateShown:  "показываются",
+
+	MessageFilterUsage: `🔎 Текущий фильтр: %s
+
+Параметры (можно комбинировать):
+• from=2024-01-01 - сообщения начиная с даты
+• to=2024-03-31 - сообщения по дату включительно
+• last=30 - только последние 30 дней
+• service - учитывать служебные сообщения (вступления, закрепы)
+• text - только сообщения с текстом
+• replies - только ответы
+
+Пример: /filter last=30 text
+Сбросить: /filter reset`,
+
+	MessageFilterSet: `✅ Фильтр установлен: %s
+
+Он будет применён при следующей обработке /process`,
+
+	MessageFilterReset: `✅ Фильтр сброшен, будут учитываться все сообщения.`,
+
+	MessageFilterInvalid: `❌ Не удалось разобрать фильтр: %s
+
+Отправьте /filter, чтобы увидеть доступные параметры.`,
+
+	MessageSettingsUsage: `⚙️ Настройки анализа
+
+%s
+
+Изменить (можно несколько сразу):
+• min=3 - учитывать авторов от 3 сообщений
+• mentions=on|off - собирать упоминания
+• case=on|off - различать регистр имён
+• channels=on|off - считать участниками каналы и анонимных админов
+
+Пример: /settings min=2 mentions=off
+Вернуть значения по умолчанию: /settings reset`,
+
+	MessageSettingsState: `• Минимум сообщений от автора: %d
+• Упоминания: %s
+• Учёт регистра: %s
+• Каналы и анонимные админы: %s`,
+
+	MessageSettingsUpdated: `✅ Настройки обновлены
+
+%s`,
+
+	MessageSettingsInvalid: `❌ Неизвестный или некорректный параметр: %s
+
+Отправьте /settings, чтобы увидеть доступные параметры.`,
+
+	MessageSwitchOn:  "вкл",
+	MessageSwitchOff: "выкл",
+
+	// Контакты
+	MessageContactsUsage: `📇 Извлечение телефонов и email: %s
+
+⚠️ Контакты участников - персональные данные. Включайте извлечение, только если у вас есть основания их обрабатывать.
+
+• /contacts on - включить для текущей сессии
+• /contacts off - выключить`,
+
+	MessageContactsOn: `⚠️ Извлечение контактов включено.
+
+При следующей обработке бот найдёт телефоны и email, опубликованные участниками, и приложит их отдельным листом Contacts и файлом contacts.csv.
+Бот не сохраняет и не логирует найденные контакты. Выключить: /contacts off`,
+
+	MessageContactsOff: `✅ Извлечение контактов выключено.`,
+
+	MessageContactsDisabled: `🔒 Извлечение контактов отключено администратором бота.`,
+
+	MessageContactsExported: `⚠️ Найдено контактов: %d
+
+Файл contacts.csv и лист Contacts содержат персональные данные участников. Не пересылайте их третьим лицам и удалите, когда они станут не нужны.`,
+
+	// Списки игнорирования
+	MessageIgnoreUsage: `ℹ️ Укажите участников через пробел:
+
+• @username - по имени пользователя
+• id:123456 - по ID аккаунта
+• /regexp/ - по регулярному выражению
+
+Пример: /ignore @spambot id:123456 /^promo/
+
+Правила /allow имеют приоритет над /ignore.`,
+
+	MessageIgnoreAdded: `🚫 Добавлено в список игнорирования: <code>%s</code>
+
+Списки сохраняются между сессиями. Посмотреть: /ignorelist`,
+
+	MessageAllowAdded: `✅ Добавлено в список исключений: <code>%s</code>
+
+Эти участники не будут скрыты правилами /ignore.`,
+
+	MessageAllowWithoutIgnore: `⚠️ Добавлено в список исключений: <code>%s</code>
+
+Список игнорирования пуст, поэтому исключения пока ни на что не влияют: они только отменяют правила /ignore и не ограничивают результат разрешёнными участниками.`,
+
+	MessageIgnoreRemoved: `✅ Удалено из списка: <code>%s</code>`,
+
+	MessageIgnoreNotFound: `⚠️ Не найдено в списке: <code>%s</code>
+
+Посмотреть текущие правила: /ignorelist`,
+
+	MessageIgnoreInvalid: `❌ Некорректное правило: <code>%s</code>
+
+Отправьте /ignore без параметров, чтобы увидеть формат.`,
+
+	MessageIgnoreListEmpty: `📭 Списки игнорирования пусты.
+
+Добавить участника: /ignore @username`,
+
+	MessageIgnoreList: `🚫 Игнорируются:
+%s
+
+✅ Исключения (allow):
+%s
+
+Исключения только отменяют правила игнорирования: участник из обоих списков остаётся в результате. Остальные участники показываются как обычно.`,
+
+	// Язык
+	MessageLangUsage: `🌐 Язык сообщений: %s
+
+• /lang ru - русский
+• /lang en - English
+• /lang auto - как в настройках Telegram`,
+
+	MessageLangSet: `✅ Язык сообщений: русский`,
+
+	MessageLangAuto: `✅ Язык сообщений будет выбираться по настройкам Telegram.`,
+
+	MessageLangNameRU:   "русский",
+	MessageLangNameEN:   "English",
+	MessageLangNameAuto: "как в Telegram (%s)",
+
+	// Склонения: формы для 1, 2-4 и 5+
+	MessagePluralFiles:    "файл|файла|файлов",
+	MessagePluralMessages: "сообщение|сообщения|сообщений",
+	MessagePluralAuthors:  "автор|автора|авторов",
+	MessagePluralMinutes:  "минута|минуты|минут",
+	MessagePluralHours:    "час|часа|часов",
+
+	// Отмена
+	MessageCancelled: `❌ Операция отменена.
+
+Все загруженные файлы удалены.
+Используйте /start для начала заново.`,
+
+	MessageNothingToCancel: `ℹ️ Нечего отменять.
+
+Используйте /upload для загрузки файлов.`,
+
+	// Доступ
+	MessageAccessDenied: `🔒 У вас нет доступа к этому боту.
+
+Чтобы получить доступ, отправьте администратору бота ваш ID: <code>%d</code>`,
+
+	MessageAccessChatDenied: `🔒 Бот не работает в этом чате.
+
+Напишите боту в личные сообщения или попросите администратора добавить чат <code>%d</code>.`,
+
+	MessageAccessPrivateOnly: `🔒 Бот работает только в личных сообщениях. Напишите ему напрямую.`,
+
+	// Квоты
+	MessageQuotaRate: `⏳ Слишком много команд подряд. Подождите %s.`,
+
+	MessageQuotaProcess: `⏳ Лимит обработок исчерпан. Следующая обработка будет доступна через %s.`,
+
+	MessageQuotaUpload: `⏳ Суточный лимит загрузки (%d MB) исчерпан. Файл можно будет отправить через %s.`,
+
+	MessageQuotaBanned: `🚫 Доступ временно ограничен из-за повторных превышений лимитов. Попробуйте через %s.`,
+
+	// Администрирование
+	MessageAdminHelp: `🛠 <b>Команды администратора</b>
+
+/admin_stats – состояние бота: сессии, очереди, диск, время работы
+/admin_sessions – активные сессии
+/admin_kill &lt;ID пользователя или сессии&gt; – сбросить сессию и удалить её файлы
+/admin_broadcast &lt;текст&gt; – уведомление всем пользователям с активными сессиями
+/admin_loglevel [debug|info|warn|error] – уровень логирования
+/admin_bans – временные блокировки
+/admin_unban &lt;ID пользователя&gt; – снять блокировку`,
+
+	MessageAdminStats: `🛠 <b>Состояние бота</b>
+
+Время работы: %s
+Активных сессий: %d (файлов: %d)
+Временных блокировок: %d
+Обновлений в обработке: %d
+Сообщений в очереди отправки: %d
+TEMP_DIR: занято %s, свободно %s
+Уровень логов: %s`,
+
+	MessageAdminUnknown: `н/д`,
+
+	MessageAdminSessions: `👥 <b>Сессии: %d</b>
+
+%s`,
+
+	MessageAdminSessionLine: `<code>%s</code> · пользователь <code>%d</code> · %s · файлов: %d · возраст %s · простой %s`,
+
+	MessageAdminSessionsMore: `… и ещё %d`,
+
+	MessageAdminNoSessions: `👥 Активных сессий нет.`,
+
+	MessageAdminKillUsage: `Использование: /admin_kill &lt;ID пользователя или сессии&gt;
+
+ID можно посмотреть в /admin_sessions.`,
+
+	MessageAdminKillNotFound: `❌ Сессия <code>%s</code> не найдена.`,
+
+	MessageAdminKilled: `✅ Сессия <code>%s</code> сброшена, удалено файлов: %d.`,
+
+	MessageAdminSessionReset: `ℹ️ Ваша сессия сброшена администратором, загруженные файлы удалены.
+
+Используйте /upload, чтобы начать заново.`,
+
+	MessageAdminBroadcastUsage: `Использование: /admin_broadcast &lt;текст уведомления&gt;`,
+
+	MessageAdminBroadcast: `📢 %s`,
+
+	MessageAdminBroadcastDone: `✅ Уведомление доставлено: %d из %d.`,
+
+	MessageAdminLogLevelUsage: `Текущий уровень логов: <b>%s</b>
+
+Использование: /admin_loglevel debug|info|warn|error`,
+
+	MessageAdminLogLevelSet: `✅ Уровень логов: <b>%s</b>`,
+
+	MessageAdminBans: `🚫 <b>Временные блокировки: %d</b>
+
+%s`,
+
+	MessageAdminBanLine: `пользователь <code>%d</code> · до %s (осталось %s)`,
+
+	MessageAdminNoBans: `🚫 Временных блокировок нет.`,
+
+	MessageAdminUnbanUsage: `Использование: /admin_unban &lt;ID пользователя&gt;
+
+ID можно посмотреть в /admin_bans.`,
+
+	MessageAdminUnbanned: `✅ Блокировка пользователя <code>%d</code> снята.`,
+
+	MessageAdminUnbanNotFound: `❌ Пользователь <code>%s</code> не заблокирован.`,
+
+	// Ошибки
+	MessageUnknownCommand: `❓ Неизвестная команда. Список команд: /help`,
+
+	MessageFileUnreadable: `не удалось прочитать файл`,
+
+	MessageUnexpectedError: `❌ Неожиданная ошибка!
+
+Пожалуйста, попробуйте снова или свяжитесь с поддержкой.`,
+
+	MessageNoFiles: `📭 Нет загруженных файлов!
+
+Используйте /upload для загрузки файлов чата.`,
+
+	// Загрузка
+	MessageUploadPrompt: `📤 Отправьте файлы для анализа
+
+Поддерживаемые форматы: JSON, HTML
+Максимум файлов за раз: %d
+Максимум размер одного файла: %d МБ
+
+Вы можете отправить несколько файлов одним сообщением, или отправляйте их по одному.
+
+Когда закончите загружать файлы, отправьте /process`,
+}
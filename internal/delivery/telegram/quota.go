@@ -0,0 +1,94 @@
+package telegram
+
+import (
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/quota"
+	"github.com/MaxFando/tg-export-chat-analyzer/pkg/logger"
+)
+
+// checkRate проверяет блокировку и частоту команд до обработки сообщения.
+// Команды и файлы расходуют лимит частоты, остальные сообщения заблокированных пользователей
+// молча отбрасываются. Администраторы лимитам не подчиняются.
+func (b *Bot) checkRate(log *logger.Logger, msg *tgbotapi.Message) bool {
+	if b.access.isAdmin(msg.From) {
+		return true
+	}
+	if !msg.IsCommand() && msg.Document == nil {
+		_, banned := b.quota.Banned(msg.From.ID)
+		return !banned
+	}
+
+	d, err := b.quota.Command(msg.From.ID)
+	return b.quotaAllowed(log, msg.From.ID, msg.Chat.ID, d, err)
+}
+
+// checkProcessQuota учитывает запуск /process в квотах пользователя
+func (b *Bot) checkProcessQuota(userID, chatID int64) bool {
+	if b.access.isAdminID(userID) {
+		return true
+	}
+	d, err := b.quota.Process(userID)
+	return b.quotaAllowed(b.log(userID), userID, chatID, d, err)
+}
+
+// checkUploadQuota учитывает размер загружаемого файла в суточной квоте пользователя
+func (b *Bot) checkUploadQuota(userID, chatID int64, size int64) bool {
+	if b.access.isAdminID(userID) {
+		return true
+	}
+	d, err := b.quota.Upload(userID, size)
+	return b.quotaAllowed(b.log(userID), userID, chatID, d, err)
+}
+
+// quotaAllowed обрабатывает решение квот: учитывает отказ в метриках и логах и сообщает
+// пользователю только о первом отказе подряд
+func (b *Bot) quotaAllowed(log *logger.Logger, userID, chatID int64, d quota.Decision, err error) bool {
+	if err != nil {
+		log.Error("failed to save quotas", "error", err)
+	}
+	if d.Allowed {
+		return true
+	}
+
+	b.metrics.QuotaRejected(d.Violation)
+	if d.Banned {
+		b.metrics.UserBanned()
+		log.Warn("user temporarily banned", "violation", d.Violation, "duration", d.RetryAfter.String())
+	} else {
+		log.Info("quota exceeded", "violation", d.Violation, "retry_after", d.RetryAfter.String())
+	}
+
+	if d.Notify {
+		b.sendMessage(chatID, b.quotaText(userID, d))
+	}
+	return false
+}
+
+// quotaText возвращает сообщение об отказе с временем, через которое действие станет доступно
+func (b *Bot) quotaText(userID int64, d quota.Decision) string {
+	retry := formatRetry(d.RetryAfter)
+	switch {
+	case d.Banned || d.Violation == quota.ViolationBanned:
+		return b.text(userID, MessageQuotaBanned, retry)
+	case d.Violation == quota.ViolationProcessQuota:
+		return b.text(userID, MessageQuotaProcess, retry)
+	case d.Violation == quota.ViolationUploadQuota:
+		return b.text(userID, MessageQuotaUpload, b.uploadQuotaMB, retry)
+	default:
+		return b.text(userID, MessageQuotaRate, retry)
+	}
+}
+
+// formatRetry округляет время ожидания вверх до секунды, чтобы не показывать «0s»
+func formatRetry(d time.Duration) string {
+	if rem := d % time.Second; rem > 0 {
+		d += time.Second - rem
+	}
+	if d < time.Second {
+		d = time.Second
+	}
+	return formatDuration(d)
+}
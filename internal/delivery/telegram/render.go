@@ -0,0 +1,68 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// parseModeHTML режим разметки исходящих сообщений
+const parseModeHTML = tgbotapi.ModeHTML
+
+// htmlEscaper экранирует символы, которые Telegram считает HTML разметкой
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// tagRe находит теги при переводе сообщения в обычный текст
+var tagRe = regexp.MustCompile(`<[^>]*>`)
+
+// safeHTML фрагмент, уже размеченный для HTML; formatHTML не экранирует его повторно
+type safeHTML string
+
+// escapeHTML экранирует пользовательские данные для HTML parse mode
+func escapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+// formatHTML подставляет аргументы в шаблон сообщения, экранируя строки, ошибки и fmt.Stringer.
+// Шаблоны из messages.go считаются доверенной разметкой.
+func formatHTML(format string, args ...interface{}) string {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case safeHTML:
+			escaped[i] = string(v)
+		case string:
+			escaped[i] = escapeHTML(v)
+		case error:
+			escaped[i] = escapeHTML(v.Error())
+		case fmt.Stringer:
+			escaped[i] = escapeHTML(v.String())
+		default:
+			escaped[i] = arg
+		}
+	}
+	return fmt.Sprintf(format, escaped...)
+}
+
+// codeHTML оформляет значение моноширинным шрифтом
+func codeHTML(s string) safeHTML {
+	return safeHTML("<code>" + escapeHTML(s) + "</code>")
+}
+
+// plainText превращает HTML сообщение в обычный текст для повторной отправки без разметки
+func plainText(s string) string {
+	return html.UnescapeString(tagRe.ReplaceAllString(s, ""))
+}
+
+// isParseError сообщает, что Telegram отклонил сообщение из-за некорректной разметки
+func isParseError(err error) bool {
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == 400 && strings.Contains(apiErr.Message, "can't parse entities")
+	}
+	return false
+}
@@ -0,0 +1,243 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"golang.org/x/time/rate"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/pkg/logger"
+	"github.com/MaxFando/tg-export-chat-analyzer/pkg/metrics"
+)
+
+const (
+	// DefaultGlobalRate лимит Telegram на исходящие сообщения бота в секунду
+	DefaultGlobalRate = 30
+	// DefaultChatRate лимит исходящих сообщений в один чат в секунду
+	DefaultChatRate = 1
+	// DefaultChatBurst количество сообщений в чат, которые можно отправить без паузы
+	DefaultChatBurst = 3
+	// DefaultSendRetries количество повторов при 429 и временных ошибках сети
+	DefaultSendRetries = 3
+
+	// retryBaseDelay начальная пауза перед повтором после временной ошибки
+	retryBaseDelay = time.Second
+	// chatLaneIdle время простоя, после которого очередь чата удаляется
+	chatLaneIdle = 10 * time.Minute
+)
+
+// chatLane очередь отправки в один чат: сообщения уходят по одному и в порядке вызова
+type chatLane struct {
+	mu      sync.Mutex
+	limiter *rate.Limiter
+
+	// lastUsed и pending защищены Sender.mu; очередь с незавершёнными отправками не удаляется,
+	// иначе следующая отправка создаст вторую очередь того же чата и нарушит порядок
+	lastUsed time.Time
+	pending  int
+}
+
+// Sender отправляет сообщения с ограничением частоты на чат и на бота в целом.
+// Ответы 429 выдерживают паузу retry_after, временные ошибки сети повторяются ограниченное число раз.
+type Sender struct {
+	api     *tgbotapi.BotAPI
+	logger  *logger.Logger
+	metrics *metrics.Metrics
+	global  *rate.Limiter
+	retries int
+
+	chatRate  rate.Limit
+	chatBurst int
+
+	// pending запросы, ожидающие очереди или выполняющиеся
+	pending atomic.Int64
+
+	mu        sync.Mutex
+	lanes     map[int64]*chatLane
+	lastSweep time.Time
+}
+
+// SenderConfig параметры очереди отправки
+type SenderConfig struct {
+	GlobalRate float64
+	ChatRate   float64
+	ChatBurst  int
+	Retries    int
+}
+
+// NewSender создаёт очередь отправки; нулевые параметры заменяются значениями по умолчанию.
+// m может быть nil, если метрики выключены.
+func NewSender(api *tgbotapi.BotAPI, log *logger.Logger, m *metrics.Metrics, cfg SenderConfig) *Sender {
+	if cfg.GlobalRate <= 0 {
+		cfg.GlobalRate = DefaultGlobalRate
+	}
+	if cfg.ChatRate <= 0 {
+		cfg.ChatRate = DefaultChatRate
+	}
+	if cfg.ChatBurst <= 0 {
+		cfg.ChatBurst = DefaultChatBurst
+	}
+	if cfg.Retries < 0 {
+		cfg.Retries = 0
+	}
+
+	return &Sender{
+		api:       api,
+		logger:    log,
+		metrics:   m,
+		global:    rate.NewLimiter(rate.Limit(cfg.GlobalRate), int(cfg.GlobalRate)),
+		retries:   cfg.Retries,
+		chatRate:  rate.Limit(cfg.ChatRate),
+		chatBurst: cfg.ChatBurst,
+		lanes:     make(map[int64]*chatLane),
+		lastSweep: time.Now(),
+	}
+}
+
+// Send отправляет сообщение, дожидаясь своей очереди в чате
+func (s *Sender) Send(chatID int64, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	var msg tgbotapi.Message
+	err := s.do(chatID, 1, func() error {
+		var err error
+		msg, err = s.api.Send(c)
+		return err
+	})
+	return msg, err
+}
+
+// SendMediaGroup отправляет медиагруппу; каждый файл группы расходует отдельный токен
+func (s *Sender) SendMediaGroup(cfg tgbotapi.MediaGroupConfig) ([]tgbotapi.Message, error) {
+	var msgs []tgbotapi.Message
+	err := s.do(cfg.ChatID, len(cfg.Media), func() error {
+		var err error
+		msgs, err = s.api.SendMediaGroup(cfg)
+		return err
+	})
+	return msgs, err
+}
+
+// Pending возвращает количество запросов в очереди отправки
+func (s *Sender) Pending() int64 {
+	return s.pending.Load()
+}
+
+// do выполняет запрос в очереди чата с учётом лимитов и политики повторов
+func (s *Sender) do(chatID int64, tokens int, send func() error) error {
+	s.pending.Add(1)
+	defer s.pending.Add(-1)
+
+	lane := s.lane(chatID)
+	defer s.release(lane)
+	lane.mu.Lock()
+	defer lane.mu.Unlock()
+
+	ctx := context.Background()
+	delay := retryBaseDelay
+
+	for attempt := 0; ; attempt++ {
+		if err := s.wait(ctx, lane.limiter, tokens); err != nil {
+			return err
+		}
+
+		err := send()
+		if err == nil {
+			return nil
+		}
+		s.metrics.APIError(errorCode(err))
+
+		pause, retryable := s.retryPause(err, delay)
+		if !retryable || attempt >= s.retries {
+			return err
+		}
+
+		s.logger.Warn("telegram request failed, retrying", "error", err, "attempt", attempt+1, "pause", pause.String())
+		time.Sleep(pause)
+		delay *= 2
+	}
+}
+
+// wait резервирует токены в лимитах чата и бота; группы больше burst ждут токены по одному
+func (s *Sender) wait(ctx context.Context, chat *rate.Limiter, tokens int) error {
+	for tokens > 0 {
+		n := min(tokens, chat.Burst(), s.global.Burst())
+		if err := chat.WaitN(ctx, n); err != nil {
+			return err
+		}
+		if err := s.global.WaitN(ctx, n); err != nil {
+			return err
+		}
+		tokens -= n
+	}
+	return nil
+}
+
+// retryPause определяет, можно ли повторить запрос и сколько ждать перед повтором
+func (s *Sender) retryPause(err error, backoff time.Duration) (time.Duration, bool) {
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.Code == 429:
+			if apiErr.RetryAfter > 0 {
+				return time.Duration(apiErr.RetryAfter) * time.Second, true
+			}
+			return backoff, true
+		case apiErr.Code >= 500:
+			return backoff, true
+		default:
+			// Ошибки запроса (разметка, недоступный чат) повтор не исправит
+			return 0, false
+		}
+	}
+
+	// Остальные ошибки — сетевые: таймауты, разрывы соединения
+	return backoff, true
+}
+
+// errorCode возвращает код ошибки Bot API для метрик или "network" для ошибок транспорта
+func errorCode(err error) string {
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) {
+		return strconv.Itoa(apiErr.Code)
+	}
+	return "network"
+}
+
+// lane возвращает очередь чата и учитывает в ней отправку, попутно удаляя давно неиспользуемые очереди.
+// После отправки очередь нужно освободить через release.
+func (s *Sender) lane(chatID int64) *chatLane {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) > chatLaneIdle {
+		for id, l := range s.lanes {
+			if l.pending == 0 && now.Sub(l.lastUsed) > chatLaneIdle {
+				delete(s.lanes, id)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	l, ok := s.lanes[chatID]
+	if !ok {
+		l = &chatLane{limiter: rate.NewLimiter(s.chatRate, s.chatBurst)}
+		s.lanes[chatID] = l
+	}
+	l.lastUsed = now
+	l.pending++
+	return l
+}
+
+// release отмечает завершение отправки в очереди чата
+func (s *Sender) release(l *chatLane) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l.pending--
+	l.lastUsed = time.Now()
+}
@@ -0,0 +1,254 @@
+package telegram
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/filter"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/session"
+)
+
+// cmdBots обрабатывает команду /bots: включает или выключает исключение ботов из результата
+func (b *Bot) cmdBots(userID, chatID int64, args string) {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "hide", "off":
+		b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+			s.ExcludeBots = true
+		})
+		b.sendMessage(chatID, b.text(userID, MessageBotsHidden))
+	case "show", "on":
+		b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+			s.ExcludeBots = false
+		})
+		b.sendMessage(chatID, b.text(userID, MessageBotsShown))
+	default:
+		state := b.text(userID, MessageBotsStateShown)
+		if b.sessionManager.GetSettings(userID).ExcludeBots {
+			state = b.text(userID, MessageBotsStateHidden)
+		}
+		b.sendMessage(chatID, b.text(userID, MessageBotsUsage, state))
+	}
+}
+
+// cmdCharts обрабатывает команду /charts: включает или выключает отправку графиков после обработки
+func (b *Bot) cmdCharts(userID, chatID int64, args string) {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "on", "show":
+		b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+			s.HideCharts = false
+		})
+		b.sendMessage(chatID, b.text(userID, MessageChartsOn))
+	case "off", "hide":
+		b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+			s.HideCharts = true
+		})
+		b.sendMessage(chatID, b.text(userID, MessageChartsOff))
+	default:
+		state := b.text(userID, MessageSwitchOn)
+		if b.sessionManager.GetSettings(userID).HideCharts {
+			state = b.text(userID, MessageSwitchOff)
+		}
+		b.sendMessage(chatID, b.text(userID, MessageChartsUsage, state))
+	}
+}
+
+// cmdLang обрабатывает команду /lang: выбирает язык сообщений или возвращает язык из профиля Telegram
+func (b *Bot) cmdLang(userID, chatID int64, args string) {
+	args = strings.ToLower(strings.TrimSpace(args))
+
+	if args == "auto" {
+		b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+			s.Language = ""
+		})
+		b.sendMessage(chatID, b.text(userID, MessageLangAuto))
+		return
+	}
+
+	lang, ok := ParseLang(args)
+	if !ok {
+		b.sendMessage(chatID, b.text(userID, MessageLangUsage, safeHTML(b.langName(userID))))
+		return
+	}
+
+	b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+		s.Language = string(lang)
+	})
+	b.sendMessage(chatID, b.text(userID, MessageLangSet))
+}
+
+// langName описывает текущий выбор языка пользователя
+func (b *Bot) langName(userID int64) string {
+	names := map[Lang]MessageID{
+		LangRU: MessageLangNameRU,
+		LangEN: MessageLangNameEN,
+	}
+
+	current := b.text(userID, names[b.lang(userID)])
+	if _, ok := ParseLang(b.sessionManager.GetSettings(userID).Language); ok {
+		return current
+	}
+	return b.text(userID, MessageLangNameAuto, current)
+}
+
+// cmdContacts обрабатывает команду /contacts: явное включение извлечения телефонов и email
+func (b *Bot) cmdContacts(userID, chatID int64, args string) {
+	if !b.contactsEnabled {
+		b.sendMessage(chatID, b.text(userID, MessageContactsDisabled))
+		return
+	}
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "on":
+		b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+			s.ExtractContacts = true
+		})
+		b.sendMessage(chatID, b.text(userID, MessageContactsOn))
+	case "off":
+		b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+			s.ExtractContacts = false
+		})
+		b.sendMessage(chatID, b.text(userID, MessageContactsOff))
+	default:
+		state := b.text(userID, MessageSwitchOff)
+		if b.sessionManager.GetSettings(userID).ExtractContacts {
+			state = b.text(userID, MessageSwitchOn)
+		}
+		b.sendMessage(chatID, b.text(userID, MessageContactsUsage, state))
+	}
+}
+
+// cmdFilter обрабатывает команду /filter: показывает, задаёт или сбрасывает фильтр событий
+func (b *Bot) cmdFilter(userID, chatID int64, args string) {
+	args = strings.TrimSpace(args)
+
+	switch strings.ToLower(args) {
+	case "":
+		spec := b.sessionManager.GetSettings(userID).Filter
+		b.sendMessage(chatID, b.text(userID, MessageFilterUsage, b.filterText(userID, spec)))
+		return
+	case "reset", "off":
+		b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+			s.Filter = filter.Spec{}
+		})
+		b.sendMessage(chatID, b.text(userID, MessageFilterReset))
+		return
+	}
+
+	spec, err := filter.Parse(args)
+	if err != nil {
+		b.sendMessage(chatID, b.text(userID, MessageFilterInvalid, err.Error()))
+		return
+	}
+
+	b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+		s.Filter = spec
+	})
+	b.sendMessage(chatID, b.text(userID, MessageFilterSet, b.filterText(userID, spec)))
+}
+
+// cmdSettings обрабатывает команду /settings: показывает или переопределяет параметры извлечения
+func (b *Bot) cmdSettings(userID, chatID int64, args string) {
+	args = strings.TrimSpace(args)
+
+	switch strings.ToLower(args) {
+	case "":
+		b.sendMessage(chatID, b.text(userID, MessageSettingsUsage, b.describeSettings(userID)))
+		return
+	case "reset":
+		b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+			s.MinMessages = nil
+			s.IncludeMentions = nil
+			s.CaseSensitive = nil
+			s.IncludeChannels = false
+		})
+		b.sendMessage(chatID, b.text(userID, MessageSettingsUpdated, b.describeSettings(userID)))
+		return
+	}
+
+	// Сначала разбираем все параметры, чтобы не применять их частично
+	var updates []func(*session.Settings)
+	for _, field := range strings.Fields(args) {
+		key, value, _ := strings.Cut(strings.ToLower(field), "=")
+
+		switch key {
+		case "min":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				b.sendMessage(chatID, b.text(userID, MessageSettingsInvalid, field))
+				return
+			}
+			updates = append(updates, func(s *session.Settings) { s.MinMessages = &n })
+		case "mentions", "case", "channels":
+			v, ok := parseSwitch(value)
+			if !ok {
+				b.sendMessage(chatID, b.text(userID, MessageSettingsInvalid, field))
+				return
+			}
+			switch key {
+			case "mentions":
+				updates = append(updates, func(s *session.Settings) { s.IncludeMentions = &v })
+			case "case":
+				updates = append(updates, func(s *session.Settings) { s.CaseSensitive = &v })
+			default:
+				updates = append(updates, func(s *session.Settings) { s.IncludeChannels = v })
+			}
+		default:
+			b.sendMessage(chatID, b.text(userID, MessageSettingsInvalid, field))
+			return
+		}
+	}
+
+	b.sessionManager.UpdateSettings(userID, func(s *session.Settings) {
+		for _, update := range updates {
+			update(s)
+		}
+	})
+	b.sendMessage(chatID, b.text(userID, MessageSettingsUpdated, b.describeSettings(userID)))
+}
+
+// describeSettings описывает параметры извлечения, которые действуют для пользователя
+func (b *Bot) describeSettings(userID int64) safeHTML {
+	settings := b.sessionManager.GetSettings(userID)
+	effective := b.effectiveDefaults(settings)
+
+	return safeHTML(b.text(userID, MessageSettingsState,
+		effective.MinMessages,
+		b.switchLabel(userID, effective.IncludeMentions),
+		b.switchLabel(userID, effective.CaseSensitive),
+		b.switchLabel(userID, settings.IncludeChannels)))
+}
+
+// effectiveDefaults накладывает переопределения сессии на настройки извлечения из конфигурации
+func (b *Bot) effectiveDefaults(settings session.Settings) extractorDefaults {
+	effective := b.defaults
+	if settings.MinMessages != nil {
+		effective.MinMessages = *settings.MinMessages
+	}
+	if settings.IncludeMentions != nil {
+		effective.IncludeMentions = *settings.IncludeMentions
+	}
+	if settings.CaseSensitive != nil {
+		effective.CaseSensitive = *settings.CaseSensitive
+	}
+	return effective
+}
+
+// parseSwitch разбирает значение переключателя on/off
+func parseSwitch(value string) (bool, bool) {
+	switch value {
+	case "on", "yes", "true", "1":
+		return true, true
+	case "off", "no", "false", "0":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// switchLabel возвращает подпись состояния переключателя
+func (b *Bot) switchLabel(userID int64, v bool) string {
+	if v {
+		return b.text(userID, MessageSwitchOn)
+	}
+	return b.text(userID, MessageSwitchOff)
+}
@@ -0,0 +1,142 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/export"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/filter"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/participant"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/stats"
+)
+
+// statsWeeksLimit количество последних недель в текстовой сводке активности
+const statsWeeksLimit = 4
+
+// statsDateLayout формат дат в сводке активности
+const statsDateLayout = "02.01.2006"
+
+// cmdStats обрабатывает команду /stats: сводка активности по загруженным файлам без их удаления
+func (b *Bot) cmdStats(userID, chatID int64) {
+	sess := b.sessionManager.Get(userID)
+	if sess == nil || len(sess.Files) == 0 {
+		b.sendMessage(chatID, b.text(userID, MessageNoFiles))
+		return
+	}
+
+	ctx, span := tracer.Start(context.Background(), "telegram.Stats",
+		trace.WithAttributes(attribute.Int("files.count", len(sess.Files))))
+	defer span.End()
+
+	events, index, spec, ok := b.loadEvents(ctx, userID, chatID, sess.Files)
+	if !ok {
+		return
+	}
+
+	report := stats.Compute(events, index)
+	if report.Empty() {
+		b.sendMessage(chatID, b.text(userID, MessageNoEventsForFilter, b.filterText(userID, spec)))
+		return
+	}
+
+	text := b.formatStats(userID, report)
+	if !spec.IsEmpty() {
+		text += b.text(userID, MessageActiveFilter, b.filterText(userID, spec))
+	}
+	b.sendMessage(chatID, text)
+}
+
+// formatStats форматирует компактную сводку активности
+func (b *Bot) formatStats(userID int64, report stats.Report) string {
+	busiest := report.BusiestDay()
+	weekday, hour, peak := report.PeakHour()
+	weekdays := b.statsLabels(userID).Weekdays
+
+	weeks := report.Weekly
+	if len(weeks) > statsWeeksLimit {
+		weeks = weeks[len(weeks)-statsWeeksLimit:]
+	}
+	lines := make([]string, 0, len(weeks))
+	for _, w := range weeks {
+		lines = append(lines, b.text(userID, MessageStatsWeek,
+			w.WeekStart.Format(statsDateLayout),
+			w.Messages, b.plural(userID, w.Messages, MessagePluralMessages),
+			w.ActiveUsers, b.plural(userID, w.ActiveUsers, MessagePluralAuthors)))
+	}
+
+	return b.text(userID, MessageStats,
+		report.From.Format(statsDateLayout),
+		report.To.Format(statsDateLayout),
+		report.Messages,
+		report.ActiveUsers,
+		report.AveragePerDay(),
+		busiest.Date.Format(statsDateLayout),
+		busiest.Messages,
+		b.plural(userID, busiest.Messages, MessagePluralMessages),
+		weekdays[weekday],
+		hour,
+		(hour+1)%24,
+		peak,
+		b.plural(userID, peak, MessagePluralMessages),
+		safeHTML(strings.Join(lines, "\n")))
+}
+
+// statsLabels возвращает подписи графиков и листов активности на языке пользователя
+func (b *Bot) statsLabels(userID int64) stats.Labels {
+	lang := b.lang(userID)
+	labels := stats.Labels{
+		Date:            translate(lang, MessageLabelDate),
+		WeekStart:       translate(lang, MessageLabelWeekStart),
+		DayHour:         translate(lang, MessageLabelDayHour),
+		Messages:        translate(lang, MessageLabelMessages),
+		ActiveUsers:     translate(lang, MessageLabelActiveUsers),
+		DailyTitle:      translate(lang, MessageLabelDaily),
+		WeeklyTitle:     translate(lang, MessageLabelWeekly),
+		HoursTitle:      translate(lang, MessageLabelHours),
+		TopAuthorsTitle: translate(lang, MessageLabelTopAuthors),
+	}
+	copy(labels.Weekdays[:], strings.Split(translate(lang, MessageWeekdays), "|"))
+	return labels
+}
+
+// reportLabels возвращает подписи отчёта на языке пользователя
+func (b *Bot) reportLabels(userID int64) export.Labels {
+	return export.Labels{
+		Stats:  b.statsLabels(userID),
+		Filter: b.filterLabels(userID),
+		Kinds:  b.kindLabels(userID),
+	}
+}
+
+// filterText возвращает описание фильтра на языке пользователя
+func (b *Bot) filterText(userID int64, spec filter.Spec) string {
+	return spec.Format(b.filterLabels(userID))
+}
+
+// filterLabels возвращает подписи описания фильтра на языке пользователя
+func (b *Bot) filterLabels(userID int64) filter.Labels {
+	lang := b.lang(userID)
+	return filter.Labels{
+		None:        translate(lang, MessageFilterNone),
+		From:        translate(lang, MessageFilterFrom),
+		To:          translate(lang, MessageFilterTo),
+		LastDays:    translate(lang, MessageFilterLastDays),
+		Service:     translate(lang, MessageFilterService),
+		OnlyText:    translate(lang, MessageFilterOnlyText),
+		OnlyReplies: translate(lang, MessageFilterOnlyReplies),
+	}
+}
+
+// kindLabels возвращает подписи типов аккаунтов на языке пользователя
+func (b *Bot) kindLabels(userID int64) participant.Labels {
+	lang := b.lang(userID)
+	return participant.Labels{
+		User:           translate(lang, MessageKindUser),
+		Bot:            translate(lang, MessageKindBot),
+		Channel:        translate(lang, MessageKindChannel),
+		AnonymousAdmin: translate(lang, MessageKindAnonymousAdmin),
+	}
+}
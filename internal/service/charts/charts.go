@@ -0,0 +1,220 @@
+package charts
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"time"
+
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/wcharczuk/go-chart/v2/drawing"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/stats"
+)
+
+// Format формат изображения графика
+type Format int
+
+const (
+	FormatPNG Format = iota
+	FormatSVG
+)
+
+const (
+	width  = 1024
+	height = 512
+
+	// maxLabelLen максимальная длина подписи участника на графике
+	maxLabelLen = 20
+
+	// maxTicks примерное количество делений на осях
+	maxTicks = 10
+)
+
+// barColor единый цвет столбцов, чтобы диаграммы читались как одна шкала
+var barColor = drawing.ColorFromHex("4A90D9")
+
+// Image готовый график
+type Image struct {
+	Name  string
+	Title string
+	Bytes []byte
+}
+
+// Render строит все графики, для которых в отчёте достаточно данных.
+// Пустые labels заменяются подписями по умолчанию.
+func Render(report stats.Report, format Format, labels stats.Labels) ([]Image, error) {
+	if report.Empty() {
+		return nil, nil
+	}
+	labels = labels.OrDefault()
+
+	var images []Image
+
+	if len(report.Daily) > 1 {
+		data, err := Activity(report, format, labels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render activity chart: %w", err)
+		}
+		images = append(images, Image{Name: "activity" + format.ext(), Title: labels.DailyTitle, Bytes: data})
+	}
+
+	data, err := Hours(report, format, labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render hourly chart: %w", err)
+	}
+	images = append(images, Image{Name: "hours" + format.ext(), Title: labels.HoursTitle, Bytes: data})
+
+	if len(report.TopAuthors) > 0 {
+		data, err := TopAuthors(report, format, labels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render top authors chart: %w", err)
+		}
+		images = append(images, Image{Name: "top_authors" + format.ext(), Title: labels.TopAuthorsTitle, Bytes: data})
+	}
+
+	return images, nil
+}
+
+// Activity строит линейный график сообщений и активных участников по дням
+func Activity(report stats.Report, format Format, labels stats.Labels) ([]byte, error) {
+	dates := make([]time.Time, 0, len(report.Daily))
+	messages := make([]float64, 0, len(report.Daily))
+	users := make([]float64, 0, len(report.Daily))
+	var dateTicks []chart.Tick
+	step := int(math.Ceil(float64(len(report.Daily)) / maxTicks))
+	max := 0.0
+	for i, d := range report.Daily {
+		dates = append(dates, d.Date)
+		messages = append(messages, float64(d.Messages))
+		users = append(users, float64(d.ActiveUsers))
+		max = math.Max(max, float64(d.Messages))
+		if i%step == 0 {
+			dateTicks = append(dateTicks, chart.Tick{Value: chart.TimeToFloat64(d.Date), Label: d.Date.Format("02.01")})
+		}
+	}
+	yRange, yTicks := intScale(max)
+
+	graph := chart.Chart{
+		Title:  labels.DailyTitle,
+		Width:  width,
+		Height: height,
+		Background: chart.Style{
+			Padding: chart.Box{Top: 48, Left: 16, Right: 16, Bottom: 16},
+		},
+		XAxis: chart.XAxis{
+			Ticks: dateTicks,
+		},
+		YAxis: chart.YAxis{
+			Range: yRange,
+			Ticks: yTicks,
+		},
+		Series: []chart.Series{
+			chart.TimeSeries{Name: labels.Messages, XValues: dates, YValues: messages},
+			chart.TimeSeries{Name: labels.ActiveUsers, XValues: dates, YValues: users},
+		},
+	}
+	graph.Elements = []chart.Renderable{chart.Legend(&graph)}
+
+	return render(graph.Render, format)
+}
+
+// Hours строит столбчатую диаграмму распределения сообщений по часам суток
+func Hours(report stats.Report, format Format, labels stats.Labels) ([]byte, error) {
+	hourly := report.Hourly()
+
+	bars := make([]chart.Value, 0, len(hourly))
+	for h, n := range hourly {
+		bars = append(bars, chart.Value{Label: fmt.Sprintf("%02d", h), Value: float64(n)})
+	}
+
+	return render(barChart(labels.HoursTitle, bars, 24).Render, format)
+}
+
+// TopAuthors строит столбчатую диаграмму самых активных участников
+func TopAuthors(report stats.Report, format Format, labels stats.Labels) ([]byte, error) {
+	bars := make([]chart.Value, 0, len(report.TopAuthors))
+	for _, a := range report.TopAuthors {
+		label := truncate(a.Label())
+		// SVG рендерер библиотеки не экранирует текст, а имена участников приходят из экспорта
+		if format == FormatSVG {
+			label = html.EscapeString(label)
+		}
+		bars = append(bars, chart.Value{Label: label, Value: float64(a.Messages)})
+	}
+
+	graph := barChart(labels.TopAuthorsTitle, bars, 60)
+	graph.XAxis.TextRotationDegrees = 30
+
+	return render(graph.Render, format)
+}
+
+// barChart создаёт столбчатую диаграмму со шкалой от нуля
+func barChart(title string, bars []chart.Value, barWidth int) chart.BarChart {
+	max := 0.0
+	for i := range bars {
+		max = math.Max(max, bars[i].Value)
+		bars[i].Style = chart.Style{FillColor: barColor, StrokeColor: barColor}
+	}
+	yRange, yTicks := intScale(max)
+
+	return chart.BarChart{
+		Title:  title,
+		Width:  width,
+		Height: height,
+		Background: chart.Style{
+			Padding: chart.Box{Top: 48, Left: 16, Right: 16, Bottom: 16},
+		},
+		BarWidth: barWidth,
+		YAxis: chart.YAxis{
+			Range: yRange,
+			Ticks: yTicks,
+		},
+		Bars: bars,
+	}
+}
+
+// render отрисовывает график в выбранном формате
+func render(draw func(chart.RendererProvider, io.Writer) error, format Format) ([]byte, error) {
+	provider := chart.PNG
+	if format == FormatSVG {
+		provider = chart.SVG
+	}
+
+	var buf bytes.Buffer
+	if err := draw(provider, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// intScale строит шкалу от нуля с целыми делениями, покрывающую значение max
+func intScale(max float64) (*chart.ContinuousRange, []chart.Tick) {
+	step := math.Max(1, math.Ceil(max/maxTicks))
+	top := math.Max(step, math.Ceil(max/step)*step)
+
+	ticks := make([]chart.Tick, 0, int(top/step)+1)
+	for v := 0.0; v <= top; v += step {
+		ticks = append(ticks, chart.Tick{Value: v, Label: fmt.Sprintf("%.0f", v)})
+	}
+	return &chart.ContinuousRange{Min: 0, Max: top}, ticks
+}
+
+// truncate сокращает длинные подписи, чтобы они не перекрывали соседние столбцы
+func truncate(label string) string {
+	runes := []rune(label)
+	if len(runes) <= maxLabelLen {
+		return label
+	}
+	return string(runes[:maxLabelLen-1]) + "…"
+}
+
+// ext возвращает расширение файла для формата
+func (f Format) ext() string {
+	if f == FormatSVG {
+		return ".svg"
+	}
+	return ".png"
+}
@@ -0,0 +1,114 @@
+package export
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/stats"
+)
+
+const (
+	sheetActivityDaily  = "Activity by day"
+	sheetActivityWeekly = "Activity by week"
+	sheetHeatmap        = "Heatmap"
+)
+
+// statsDateLayout формат дат на листах активности
+const statsDateLayout = "2006-01-02"
+
+// writeActivitySheets добавляет листы активности по дням и неделям с графиками и тепловую карту
+func writeActivitySheets(f *excelize.File, report stats.Report, labels stats.Labels) error {
+	daily := make([][]any, 0, len(report.Daily))
+	for _, d := range report.Daily {
+		daily = append(daily, []any{d.Date.Format(statsDateLayout), d.Messages, d.ActiveUsers})
+	}
+	if err := writeTable(f, sheetActivityDaily, []string{labels.Date, labels.Messages, labels.ActiveUsers}, daily, []float64{14, 14, 22}); err != nil {
+		return err
+	}
+	if err := addSeriesChart(f, sheetActivityDaily, excelize.Line, labels.DailyTitle, len(daily)); err != nil {
+		return err
+	}
+
+	weekly := make([][]any, 0, len(report.Weekly))
+	for _, w := range report.Weekly {
+		weekly = append(weekly, []any{w.WeekStart.Format(statsDateLayout), w.Messages, w.ActiveUsers})
+	}
+	if err := writeTable(f, sheetActivityWeekly, []string{labels.WeekStart, labels.Messages, labels.ActiveUsers}, weekly, []float64{14, 14, 22}); err != nil {
+		return err
+	}
+	if err := addSeriesChart(f, sheetActivityWeekly, excelize.Col, labels.WeeklyTitle, len(weekly)); err != nil {
+		return err
+	}
+
+	return writeHeatmapSheet(f, report, labels)
+}
+
+// addSeriesChart строит график по колонкам B (сообщения) и C (активные участники) с датами из колонки A
+func addSeriesChart(f *excelize.File, sheet string, chartType excelize.ChartType, title string, rows int) error {
+	if rows == 0 {
+		return nil
+	}
+
+	last := rows + 1
+	categories := fmt.Sprintf("'%s'!$A$2:$A$%d", sheet, last)
+	return f.AddChart(sheet, "E2", &excelize.Chart{
+		Type: chartType,
+		Series: []excelize.ChartSeries{
+			{
+				Name:       fmt.Sprintf("'%s'!$B$1", sheet),
+				Categories: categories,
+				Values:     fmt.Sprintf("'%s'!$B$2:$B$%d", sheet, last),
+			},
+			{
+				Name:       fmt.Sprintf("'%s'!$C$1", sheet),
+				Categories: categories,
+				Values:     fmt.Sprintf("'%s'!$C$2:$C$%d", sheet, last),
+			},
+		},
+		Title:     []excelize.RichTextRun{{Text: title}},
+		Legend:    excelize.ChartLegend{Position: "bottom"},
+		Dimension: excelize.ChartDimension{Width: 720, Height: 360},
+	})
+}
+
+// writeHeatmapSheet добавляет тепловую карту активности: дни недели по строкам, часы по колонкам
+func writeHeatmapSheet(f *excelize.File, report stats.Report, labels stats.Labels) error {
+	headers := make([]string, 0, 25)
+	headers = append(headers, labels.DayHour)
+	for h := 0; h < 24; h++ {
+		headers = append(headers, fmt.Sprintf("%02d", h))
+	}
+
+	rows := make([][]any, 0, len(labels.Weekdays))
+	for d, name := range labels.Weekdays {
+		row := make([]any, 0, 25)
+		row = append(row, name)
+		for _, n := range report.Heatmap[d] {
+			row = append(row, n)
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]float64, 25)
+	widths[0] = 12
+	for i := 1; i < len(widths); i++ {
+		widths[i] = 5
+	}
+
+	if err := writeTable(f, sheetHeatmap, headers, rows, widths); err != nil {
+		return err
+	}
+
+	return f.SetConditionalFormat(sheetHeatmap, "B2:Y8", []excelize.ConditionalFormatOptions{{
+		Type:     "3_color_scale",
+		Criteria: "=",
+		MinType:  "min",
+		MidType:  "percentile",
+		MidValue: "50",
+		MaxType:  "max",
+		MinColor: "#FFFFFF",
+		MidColor: "#FFD966",
+		MaxColor: "#E06666",
+	}})
+}
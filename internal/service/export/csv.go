@@ -0,0 +1,75 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/participant"
+)
+
+// utf8BOM помогает Excel правильно определить кодировку CSV с кириллицей
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// CSVFile готовый к отправке CSV файл
+type CSVFile struct {
+	Name  string
+	Bytes []byte
+}
+
+// ExportContentCSV экспортирует хэштеги, ссылки, домены и контакты в отдельные CSV файлы; пустые таблицы пропускаются
+func (s *Service) ExportContentCSV(result participant.Result) ([]CSVFile, error) {
+	tables := []struct {
+		name    string
+		headers []string
+		rows    [][]any
+	}{
+		{"hashtags.csv", hashtagHeaders, hashtagRows(result)},
+		{"links.csv", linkHeaders, linkRows(result)},
+		{"domains.csv", domainHeaders, domainRows(result)},
+		{"contacts.csv", contactHeaders, contactRows(result)},
+	}
+
+	var files []CSVFile
+	for _, t := range tables {
+		if len(t.rows) == 0 {
+			continue
+		}
+
+		data, err := encodeCSV(t.headers, t.rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", t.name, err)
+		}
+		files = append(files, CSVFile{Name: t.name, Bytes: data})
+	}
+
+	return files, nil
+}
+
+// encodeCSV сериализует таблицу в CSV
+func encodeCSV(headers []string, rows [][]any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(utf8BOM)
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(headers); err != nil {
+		return nil, err
+	}
+
+	record := make([]string, 0, len(headers))
+	for _, row := range rows {
+		record = record[:0]
+		for _, v := range row {
+			record = append(record, fmt.Sprint(v))
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
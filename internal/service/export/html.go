@@ -0,0 +1,126 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/lintenved/tg-exporter/exporter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/charts"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/participant"
+)
+
+//go:embed templates/report.html
+var reportTemplateSource string
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(reportTemplateSource))
+
+// htmlParticipant строка таблицы участников HTML отчёта
+type htmlParticipant struct {
+	Username   string
+	FirstName  string
+	LastName   string
+	ID         string
+	Kind       string
+	HasChannel bool
+	IsDeleted  bool
+}
+
+// htmlChart график, встроенный в HTML отчёт
+type htmlChart struct {
+	Title string
+	SVG   template.HTML
+}
+
+// htmlReport данные шаблона HTML отчёта
+type htmlReport struct {
+	ExportedAt   string
+	Filter       string
+	Messages     int
+	ActiveUsers  int
+	Period       string
+	Participants []htmlParticipant
+	Mentions     []htmlParticipant
+	Channels     []string
+	Bots         int
+	Charts       []htmlChart
+}
+
+// ExportToHTML экспортирует отчёт в один HTML файл без внешних зависимостей:
+// стили, скрипты сортировки и поиска и SVG графики встроены в документ
+func (s *Service) ExportToHTML(ctx context.Context, report Report) (out []byte, err error) {
+	_, span := tracer.Start(ctx, "export.ExportToHTML",
+		trace.WithAttributes(attribute.Int("participants.count", len(report.Result.Participants))))
+	defer func() {
+		span.SetAttributes(attribute.Int("output.size", len(out)))
+		endSpan(span, err)
+	}()
+
+	data := htmlReport{
+		ExportedAt:   time.Now().Format("02.01.2006 15:04"),
+		Messages:     report.Activity.Messages,
+		ActiveUsers:  report.Activity.ActiveUsers,
+		Participants: htmlParticipants(report.Result, report.Result.Participants, report.Labels.Kinds),
+		Mentions:     htmlParticipants(report.Result, report.Result.Mentions, report.Labels.Kinds),
+		Channels:     report.Result.Channels,
+	}
+
+	if !report.Filter.IsEmpty() {
+		data.Filter = report.Filter.Format(report.Labels.Filter)
+	}
+	if !report.Activity.Empty() {
+		data.Period = fmt.Sprintf("%s – %s",
+			report.Activity.From.Format("02.01.2006"),
+			report.Activity.To.Format("02.01.2006"))
+	}
+	for _, p := range report.Result.Participants {
+		if report.Result.KindOf(p) == participant.KindBot {
+			data.Bots++
+		}
+	}
+
+	images, err := charts.Render(report.Activity, charts.FormatSVG, report.Labels.Stats)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render charts: %w", err)
+	}
+	for _, image := range images {
+		// Подписи из данных экспорта экранируются при построении SVG, поэтому график встраивается как есть
+		data.Charts = append(data.Charts, htmlChart{Title: image.Title, SVG: template.HTML(image.Bytes)})
+	}
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// htmlParticipants готовит строки таблицы участников
+func htmlParticipants(result participant.Result, participants []exporter.Participant, labels participant.Labels) []htmlParticipant {
+	rows := make([]htmlParticipant, 0, len(participants))
+	for _, p := range participants {
+		username := strings.TrimSpace(p.Username)
+		if username != "" && !strings.HasPrefix(username, "@") {
+			username = "@" + username
+		}
+		rows = append(rows, htmlParticipant{
+			Username:   username,
+			FirstName:  p.FirstName,
+			LastName:   p.LastName,
+			ID:         p.ID,
+			Kind:       result.KindOf(p).Format(labels),
+			HasChannel: p.HasChannel,
+			IsDeleted:  p.IsDeleted,
+		})
+	}
+	return rows
+}
@@ -1,36 +1,196 @@
 package export
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lintenved/tg-exporter/exporter"
+	"github.com/xuri/excelize/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/filter"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/interaction"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/participant"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/stats"
 )
 
+// tracer создаёт спаны этапов экспорта
+var tracer = otel.Tracer("github.com/MaxFando/tg-export-chat-analyzer/internal/service/export")
+
+// DefaultListThreshold количество участников, начиная с которого результат отправляется в Excel
+const DefaultListThreshold = 50
+
 // Service управляет экспортом результатов
 type Service struct {
-	// поля для будущего расширения конфигурации
+	listThreshold int
+	maxMessageLen int
+}
+
+// Option настраивает Service
+type Option func(*Service)
+
+// WithListThreshold задаёт количество участников, начиная с которого результат отправляется в Excel
+func WithListThreshold(n int) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.listThreshold = n
+		}
+	}
+}
+
+// WithMaxMessageLen задаёт максимальную длину одного сообщения со списком участников
+func WithMaxMessageLen(n int) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.maxMessageLen = n
+		}
+	}
 }
 
 // New создаёт новый ExportService
-func New() *Service {
-	return &Service{}
+func New(opts ...Option) *Service {
+	s := &Service{
+		listThreshold: DefaultListThreshold,
+		maxMessageLen: exporter.DefaultMaxMessageLen,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ListThreshold возвращает количество участников, начиная с которого результат отправляется в Excel
+func (s *Service) ListThreshold() int {
+	return s.listThreshold
 }
 
 // ChooseFormat выбирает формат вывода на основе количества участников
 func (s *Service) ChooseFormat(participantsCount int) exporter.OutputFormat {
-	return exporter.ChooseFormat(participantsCount)
+	if participantsCount < s.listThreshold {
+		return exporter.OutputTelegramList
+	}
+	return exporter.OutputExcel
+}
+
+// Report объединяет результат извлечения участников и дополнительные разделы отчёта
+type Report struct {
+	Result       participant.Result
+	Interactions interaction.Graph
+	Filter       filter.Spec
+	Activity     stats.Report
+	// Labels подписи отчёта на языке пользователя; пустые — на русском
+	Labels Labels
+}
+
+// Labels подписи разделов отчёта на языке пользователя
+type Labels struct {
+	Stats  stats.Labels
+	Filter filter.Labels
+	Kinds  participant.Labels
 }
 
 // ExportToExcel экспортирует результат в Excel
 func (s *Service) ExportToExcel(result exporter.ParticipantsResult) ([]byte, error) {
-	return exporter.ExportExcel(result, exporter.Options{
-		ExportedAt: time.Now(),
+	return s.ExportReportToExcel(context.Background(), Report{Result: participant.Result{ParticipantsResult: result}})
+}
+
+// ExportReportToExcel экспортирует отчёт в Excel, добавляя листы дополнительных разделов
+func (s *Service) ExportReportToExcel(ctx context.Context, report Report) (data []byte, err error) {
+	_, span := tracer.Start(ctx, "export.ExportReportToExcel",
+		trace.WithAttributes(attribute.Int("participants.count", len(report.Result.Participants))))
+	defer func() {
+		span.SetAttributes(attribute.Int("output.size", len(data)))
+		endSpan(span, err)
+	}()
+
+	exportedAt := time.Now()
+
+	base, err := exporter.ExportExcel(report.Result.ParticipantsResult, exporter.Options{
+		ExportedAt: exportedAt,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(base))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open generated workbook: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	sheets := map[string][]exporter.Participant{
+		sheetParticipants: report.Result.Participants,
+		sheetMentions:     report.Result.Mentions,
+	}
+	for sheet, people := range sheets {
+		if err := writeKindColumn(f, sheet, people, report.Result, report.Labels.Kinds); err != nil {
+			return nil, fmt.Errorf("failed to write kind column: %w", err)
+		}
+	}
+
+	if err := writeContentSheets(f, report.Result); err != nil {
+		return nil, fmt.Errorf("failed to write content sheets: %w", err)
+	}
+
+	if !report.Activity.Empty() {
+		if err := writeActivitySheets(f, report.Activity, report.Labels.Stats.OrDefault()); err != nil {
+			return nil, fmt.Errorf("failed to write activity sheets: %w", err)
+		}
+	}
+
+	if !report.Filter.IsEmpty() {
+		if err := writeFilterSheet(f, report.Filter, report.Labels.Filter, exportedAt); err != nil {
+			return nil, fmt.Errorf("failed to write filter sheet: %w", err)
+		}
+	}
+
+	if !report.Interactions.Empty() {
+		if err := writeInteractionsSheet(f, report.Interactions); err != nil {
+			return nil, fmt.Errorf("failed to write interactions sheet: %w", err)
+		}
+		if err := writeForwardSourcesSheet(f, report.Interactions); err != nil {
+			return nil, fmt.Errorf("failed to write forward sources sheet: %w", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write workbook: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportGraphML экспортирует граф взаимодействий в GraphML
+func (s *Service) ExportGraphML(graph interaction.Graph) []byte {
+	return graph.GraphML()
+}
+
+// ExportDOT экспортирует граф взаимодействий в Graphviz DOT
+func (s *Service) ExportDOT(graph interaction.Graph) []byte {
+	return graph.DOT()
 }
 
 // FormatForTelegram форматирует список участников для Telegram
 func (s *Service) FormatForTelegram(participants []exporter.Participant) []string {
-	return exporter.FormatTelegramUserList(participants, exporter.DefaultMaxMessageLen)
+	return exporter.FormatTelegramUserList(participants, s.maxMessageLen)
+}
+
+// FormatResultForTelegram форматирует список участников, помечая аккаунты, которые не являются пользователями
+func (s *Service) FormatResultForTelegram(result participant.Result, labels participant.Labels) []string {
+	participants := make([]exporter.Participant, 0, len(result.Participants))
+	for _, p := range result.Participants {
+		if kind := result.KindOf(p); kind != participant.KindUser && strings.TrimSpace(p.Username) != "" {
+			p.Username = fmt.Sprintf("%s (%s)", p.Username, kind.Format(labels))
+		}
+		participants = append(participants, p)
+	}
+	return s.FormatForTelegram(participants)
 }
 
 // Export выполняет экспорт в выбранный формат
@@ -46,3 +206,12 @@ func (s *Service) Export(result exporter.ParticipantsResult) (interface{}, error
 		return s.FormatForTelegram(result.Participants), nil
 	}
 }
+
+// endSpan завершает спан, отмечая ошибку экспорта
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "export failed")
+	}
+	span.End()
+}
@@ -0,0 +1,254 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lintenved/tg-exporter/exporter"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/filter"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/interaction"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/participant"
+)
+
+const (
+	sheetParticipants   = "Participants"
+	sheetMentions       = "Mentions"
+	sheetInteractions   = "Interactions"
+	sheetForwardSources = "Forward sources"
+	sheetFilter         = "Filter"
+	sheetHashtags       = "Hashtags"
+	sheetLinks          = "Links"
+	sheetDomains        = "Domains"
+	sheetContacts       = "Contacts"
+)
+
+// kindColumn колонка с типом аккаунта, следующая за колонками exporter.ExportExcel
+const kindColumn = "G"
+
+// writeKindColumn дописывает тип аккаунта к листу участников. Строки сопоставляются с people
+// по порядку: exporter.ExportExcel пропускает удалённые аккаунты и записи без имени.
+func writeKindColumn(f *excelize.File, sheet string, people []exporter.Participant, result participant.Result, labels participant.Labels) error {
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+	})
+
+	header := kindColumn + "1"
+	if err := f.SetCellValue(sheet, header, "Тип аккаунта"); err != nil {
+		return err
+	}
+	_ = f.SetCellStyle(sheet, header, header, headerStyle)
+	_ = f.SetColWidth(sheet, kindColumn, kindColumn, 18)
+
+	row := 2
+	for _, p := range people {
+		if p.IsDeleted || strings.TrimSpace(p.Username) == "" && strings.TrimSpace(p.FirstName+p.LastName) == "" {
+			continue
+		}
+
+		cell := kindColumn + strconv.Itoa(row)
+		if err := f.SetCellValue(sheet, cell, result.KindOf(p).Format(labels)); err != nil {
+			return err
+		}
+		row++
+	}
+
+	return nil
+}
+
+// writeInteractionsSheet добавляет лист со взвешенными рёбрами графа ответов и пересылок
+func writeInteractionsSheet(f *excelize.File, graph interaction.Graph) error {
+	headers := []string{"Тип", "От", "Кому", "Вес"}
+
+	label := graph.Labeler()
+	rows := make([][]any, 0, len(graph.Edges))
+	for _, e := range graph.Edges {
+		kind := "ответ"
+		if e.Kind == interaction.EdgeForward {
+			kind = "пересылка"
+		}
+		rows = append(rows, []any{kind, label(e.From), label(e.To), e.Weight})
+	}
+
+	return writeTable(f, sheetInteractions, headers, rows, []float64{14, 30, 30, 10})
+}
+
+// writeForwardSourcesSheet добавляет лист с источниками пересланных сообщений
+func writeForwardSourcesSheet(f *excelize.File, graph interaction.Graph) error {
+	headers := []string{"Источник", "Тип", "Внешний", "Пересылок"}
+
+	rows := make([][]any, 0, len(graph.Sources))
+	for _, src := range graph.Sources {
+		kind := "пользователь"
+		if src.IsChannel {
+			kind = "канал"
+		}
+		external := "нет"
+		if src.External {
+			external = "да"
+		}
+		name := src.Name
+		if name == "" {
+			name = src.ID
+		}
+		rows = append(rows, []any{name, kind, external, src.Count})
+	}
+
+	return writeTable(f, sheetForwardSources, headers, rows, []float64{36, 16, 12, 12})
+}
+
+// hashtagRows строки таблицы хэштегов, общие для Excel и CSV
+func hashtagRows(result participant.Result) [][]any {
+	rows := make([][]any, 0, len(result.Hashtags))
+	for _, h := range result.Hashtags {
+		users := make([]string, 0, len(h.TopUsers))
+		for _, u := range h.TopUsers {
+			users = append(users, fmt.Sprintf("%s (%d)", u.UserID, u.Count))
+		}
+		rows = append(rows, []any{h.Tag, h.Count, strings.Join(users, ", ")})
+	}
+	return rows
+}
+
+// linkRows строки таблицы ссылок
+func linkRows(result participant.Result) [][]any {
+	rows := make([][]any, 0, len(result.Links))
+	for _, l := range result.Links {
+		rows = append(rows, []any{l.URL, l.Domain, l.Count})
+	}
+	return rows
+}
+
+// domainRows строки таблицы доменов
+func domainRows(result participant.Result) [][]any {
+	rows := make([][]any, 0, len(result.Domains))
+	for _, d := range result.Domains {
+		rows = append(rows, []any{d.Domain, d.Count})
+	}
+	return rows
+}
+
+// contactRows строки таблицы контактов
+func contactRows(result participant.Result) [][]any {
+	rows := make([][]any, 0, len(result.Contacts))
+	for _, c := range result.Contacts {
+		kind := "email"
+		if c.Type == participant.ContactPhone {
+			kind = "телефон"
+		}
+		rows = append(rows, []any{kind, c.Value, c.UserID, c.Count})
+	}
+	return rows
+}
+
+var (
+	contactHeaders = []string{"Тип", "Контакт", "Опубликовал", "Упоминаний"}
+	hashtagHeaders = []string{"Хэштег", "Упоминаний", "Самые активные авторы"}
+	linkHeaders    = []string{"Ссылка", "Домен", "Упоминаний"}
+	domainHeaders  = []string{"Домен", "Упоминаний"}
+)
+
+// writeContentSheets добавляет листы с хэштегами, ссылками, доменами и контактами, если они найдены
+func writeContentSheets(f *excelize.File, result participant.Result) error {
+	if len(result.Hashtags) > 0 {
+		if err := writeTable(f, sheetHashtags, hashtagHeaders, hashtagRows(result), []float64{28, 14, 48}); err != nil {
+			return err
+		}
+	}
+	if len(result.Links) > 0 {
+		if err := writeTable(f, sheetLinks, linkHeaders, linkRows(result), []float64{60, 28, 14}); err != nil {
+			return err
+		}
+	}
+	if len(result.Domains) > 0 {
+		if err := writeTable(f, sheetDomains, domainHeaders, domainRows(result), []float64{36, 14}); err != nil {
+			return err
+		}
+	}
+	if len(result.Contacts) > 0 {
+		if err := writeTable(f, sheetContacts, contactHeaders, contactRows(result), []float64{12, 32, 24, 14}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFilterSheet добавляет лист с параметрами фильтра, применённого перед извлечением
+func writeFilterSheet(f *excelize.File, spec filter.Spec, labels filter.Labels, exportedAt time.Time) error {
+	from, to := spec.Bounds(exportedAt)
+
+	period := func(t time.Time) string {
+		if t.IsZero() {
+			return "—"
+		}
+		return t.Format(filter.DateLayout)
+	}
+	flag := func(v bool) string {
+		if v {
+			return "да"
+		}
+		return "нет"
+	}
+
+	// Bounds возвращает исключающую верхнюю границу, в отчёте показываем последний включённый день
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, -1)
+	}
+
+	rows := [][]any{
+		{"Фильтр", spec.Format(labels)},
+		{"Период с", period(from)},
+		{"Период по", period(to)},
+		{"Служебные сообщения", flag(spec.IncludeService)},
+		{"Только с текстом", flag(spec.OnlyWithText)},
+		{"Только ответы", flag(spec.OnlyReplies)},
+	}
+
+	return writeTable(f, sheetFilter, []string{"Параметр", "Значение"}, rows, []float64{28, 48})
+}
+
+// writeTable создаёт лист с жирной шапкой, закреплённой первой строкой и заданными ширинами колонок
+func writeTable(f *excelize.File, sheet string, headers []string, rows [][]any, widths []float64) error {
+	if _, err := f.NewSheet(sheet); err != nil {
+		return err
+	}
+
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+	})
+
+	for i, h := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		if err := f.SetCellValue(sheet, cell, h); err != nil {
+			return err
+		}
+		_ = f.SetCellStyle(sheet, cell, cell, headerStyle)
+	}
+
+	_ = f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		Split:       true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+
+	for r, values := range rows {
+		for c, v := range values {
+			cell, _ := excelize.CoordinatesToCellName(c+1, r+2)
+			if err := f.SetCellValue(sheet, cell, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, w := range widths {
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		_ = f.SetColWidth(sheet, col, col, w)
+	}
+
+	return nil
+}
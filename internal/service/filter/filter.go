@@ -0,0 +1,217 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nikalively/telegram-export-parser/parser"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/metadata"
+)
+
+// DateLayout формат дат в параметрах фильтра
+const DateLayout = "2006-01-02"
+
+// Spec описывает, какие события учитываются при извлечении участников
+type Spec struct {
+	// From и To ограничивают период включительно; нулевое значение — без ограничения
+	From time.Time
+	To   time.Time
+	// LastDays ограничивает период последними N днями на момент применения
+	LastDays int
+
+	// IncludeService учитывает служебные сообщения (вступления, закрепы и т.п.) как активность
+	// их инициаторов; по умолчанию служебные сообщения не учитываются
+	IncludeService bool
+	OnlyWithText   bool
+	OnlyReplies    bool
+}
+
+// IsEmpty сообщает, что фильтр ничего не ограничивает
+func (s Spec) IsEmpty() bool {
+	return s == Spec{}
+}
+
+// Bounds возвращает фактические границы периода на момент now
+func (s Spec) Bounds(now time.Time) (from, to time.Time) {
+	from, to = s.From, s.To
+	if s.LastDays > 0 {
+		last := now.AddDate(0, 0, -s.LastDays)
+		if from.IsZero() || last.After(from) {
+			from = last
+		}
+	}
+	if !to.IsZero() {
+		// Конечная дата включается целиком
+		to = to.AddDate(0, 0, 1)
+	}
+	return from, to
+}
+
+// Apply оставляет только события, удовлетворяющие фильтру
+func (s Spec) Apply(events []parser.Event, index metadata.Index) []parser.Event {
+	if s.IsEmpty() {
+		return events
+	}
+
+	from, to := s.Bounds(time.Now())
+
+	result := make([]parser.Event, 0, len(events))
+	for _, event := range events {
+		if !from.IsZero() && event.Date.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !event.Date.Before(to) {
+			continue
+		}
+
+		msg, _ := index.Lookup(event)
+
+		if s.OnlyWithText && strings.TrimSpace(event.Text) == "" {
+			continue
+		}
+		if s.OnlyReplies && msg.ReplyToID == 0 {
+			continue
+		}
+
+		result = append(result, event)
+	}
+
+	return result
+}
+
+// Events возвращает события для анализа: события парсера без дублей из пересекающихся
+// выгрузок, отсортированные по времени, и, если фильтр их включает, служебные сообщения
+// из метаданных
+func (s Spec) Events(events []parser.Event, index metadata.Index) []parser.Event {
+	sources := [][]parser.Event{events}
+	if s.IncludeService {
+		sources = append(sources, index.ServiceEvents())
+	}
+	return parser.MergeEvents(sources)
+}
+
+// Parse разбирает параметры фильтра вида "from=2024-01-01 to=2024-02-01 last=30 service text replies"
+func Parse(args string) (Spec, error) {
+	var spec Spec
+
+	for _, field := range strings.Fields(args) {
+		key, value, hasValue := strings.Cut(strings.ToLower(field), "=")
+
+		switch key {
+		case "from", "to":
+			if !hasValue {
+				return Spec{}, fmt.Errorf("missing date for %q", key)
+			}
+			date, err := time.Parse(DateLayout, value)
+			if err != nil {
+				return Spec{}, fmt.Errorf("invalid date %q: expected YYYY-MM-DD", value)
+			}
+			if key == "from" {
+				spec.From = date
+			} else {
+				spec.To = date
+			}
+		case "last":
+			days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+			if err != nil || days <= 0 {
+				return Spec{}, fmt.Errorf("invalid number of days %q", value)
+			}
+			spec.LastDays = days
+		case "service":
+			spec.IncludeService = true
+		case "text":
+			spec.OnlyWithText = true
+		case "replies":
+			spec.OnlyReplies = true
+		default:
+			return Spec{}, fmt.Errorf("unknown filter option %q", field)
+		}
+	}
+
+	if err := spec.Validate(); err != nil {
+		return Spec{}, err
+	}
+
+	return spec, nil
+}
+
+// Validate проверяет согласованность параметров фильтра
+func (s Spec) Validate() error {
+	if s.LastDays < 0 {
+		return fmt.Errorf("invalid number of days %d", s.LastDays)
+	}
+	if !s.From.IsZero() && !s.To.IsZero() && s.To.Before(s.From) {
+		return fmt.Errorf("end date %s is before start date %s",
+			s.To.Format(DateLayout), s.From.Format(DateLayout))
+	}
+	return nil
+}
+
+// Labels подписи описания фильтра на языке пользователя
+type Labels struct {
+	None string
+	// From, To и LastDays — шаблоны fmt с датой или числом дней
+	From        string
+	To          string
+	LastDays    string
+	Service     string
+	OnlyText    string
+	OnlyReplies string
+}
+
+// DefaultLabels подписи на русском языке
+var DefaultLabels = Labels{
+	None:        "без фильтра",
+	From:        "с %s",
+	To:          "по %s",
+	LastDays:    "последние %d дн.",
+	Service:     "со служебными сообщениями",
+	OnlyText:    "только с текстом",
+	OnlyReplies: "только ответы",
+}
+
+// OrDefault возвращает подписи по умолчанию, если подписи не заданы
+func (l Labels) OrDefault() Labels {
+	if l == (Labels{}) {
+		return DefaultLabels
+	}
+	return l
+}
+
+// String возвращает описание фильтра на русском языке
+func (s Spec) String() string {
+	return s.Format(DefaultLabels)
+}
+
+// Format возвращает описание фильтра для пользователя с заданными подписями
+func (s Spec) Format(labels Labels) string {
+	labels = labels.OrDefault()
+	if s.IsEmpty() {
+		return labels.None
+	}
+
+	var parts []string
+	if !s.From.IsZero() {
+		parts = append(parts, fmt.Sprintf(labels.From, s.From.Format(DateLayout)))
+	}
+	if !s.To.IsZero() {
+		parts = append(parts, fmt.Sprintf(labels.To, s.To.Format(DateLayout)))
+	}
+	if s.LastDays > 0 {
+		parts = append(parts, fmt.Sprintf(labels.LastDays, s.LastDays))
+	}
+	if s.IncludeService {
+		parts = append(parts, labels.Service)
+	}
+	if s.OnlyWithText {
+		parts = append(parts, labels.OnlyText)
+	}
+	if s.OnlyReplies {
+		parts = append(parts, labels.OnlyReplies)
+	}
+
+	return strings.Join(parts, ", ")
+}
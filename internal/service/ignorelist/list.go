@@ -0,0 +1,148 @@
+package ignorelist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Rule правило сопоставления участника: по username, по ID или по регулярному выражению
+type Rule struct {
+	raw string
+	id  string
+	re  *regexp.Regexp
+}
+
+// ParseRule разбирает правило:
+//   - "@name" или "name" — username без учёта регистра;
+//   - "id:123", "123" или "user123" — ID аккаунта;
+//   - "/regexp/" — регулярное выражение по username и ID.
+func ParseRule(raw string) (Rule, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Rule{}, fmt.Errorf("empty rule")
+	}
+
+	if len(raw) > 2 && strings.HasPrefix(raw, "/") && strings.HasSuffix(raw, "/") {
+		re, err := regexp.Compile(raw[1 : len(raw)-1])
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid regexp %q: %w", raw, err)
+		}
+		return Rule{raw: raw, re: re}, nil
+	}
+
+	if id, ok := strings.CutPrefix(strings.ToLower(raw), "id:"); ok {
+		if !isDigits(id) {
+			return Rule{}, fmt.Errorf("invalid id %q", raw)
+		}
+		return Rule{raw: raw, id: id}, nil
+	}
+
+	if isDigits(raw) {
+		return Rule{raw: raw, id: raw}, nil
+	}
+
+	name := strings.TrimPrefix(raw, "@")
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return Rule{}, fmt.Errorf("invalid username %q", raw)
+	}
+	return Rule{raw: "@" + name}, nil
+}
+
+// String возвращает правило в исходной записи
+func (r Rule) String() string {
+	return r.raw
+}
+
+// Match проверяет, подходит ли участник под правило
+func (r Rule) Match(id, username string) bool {
+	id = strings.TrimPrefix(strings.TrimSpace(id), "@")
+	username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+
+	switch {
+	case r.re != nil:
+		return (id != "" && r.re.MatchString(id)) || (username != "" && r.re.MatchString(username))
+	case r.id != "":
+		return id == r.id || id == "user"+r.id || id == "channel"+r.id
+	default:
+		name := strings.TrimPrefix(r.raw, "@")
+		return strings.EqualFold(username, name) || strings.EqualFold(id, name)
+	}
+}
+
+// List списки игнорируемых и разрешённых участников.
+// Разрешающие правила имеют приоритет: участник из Allow не исключается, даже если подходит под Ignore.
+// Allow только отменяет Ignore и не ограничивает результат: без правил Ignore исключений нет.
+type List struct {
+	Ignore []Rule
+	Allow  []Rule
+}
+
+// IsEmpty сообщает, что в списке нет правил
+func (l List) IsEmpty() bool {
+	return len(l.Ignore) == 0 && len(l.Allow) == 0
+}
+
+// Excludes проверяет, нужно ли исключить участника из результата
+func (l List) Excludes(id, username string) bool {
+	return matchAny(l.Ignore, id, username) && !matchAny(l.Allow, id, username)
+}
+
+// Parse читает списки из текста: по одному правилу в строке,
+// строки с "!" в начале — разрешающие правила, с "#" — комментарии.
+func Parse(r io.Reader) (List, error) {
+	var list List
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		allow := strings.HasPrefix(text, "!")
+		rule, err := ParseRule(strings.TrimPrefix(text, "!"))
+		if err != nil {
+			return List{}, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if allow {
+			list.Allow = append(list.Allow, rule)
+		} else {
+			list.Ignore = append(list.Ignore, rule)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return List{}, fmt.Errorf("failed to read list: %w", err)
+	}
+
+	return list, nil
+}
+
+// matchAny проверяет, подходит ли участник хотя бы под одно правило
+func matchAny(rules []Rule, id, username string) bool {
+	for _, r := range rules {
+		if r.Match(id, username) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDigits проверяет, что строка состоит только из цифр
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
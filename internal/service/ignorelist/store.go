@@ -0,0 +1,193 @@
+package ignorelist
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// Kind вид списка
+type Kind string
+
+const (
+	KindIgnore Kind = "ignore"
+	KindAllow  Kind = "allow"
+)
+
+// storedList формат хранения списков на диске
+type storedList struct {
+	Ignore []string `json:"ignore"`
+	Allow  []string `json:"allow"`
+}
+
+// Store хранит списки пользователей в JSON файлах, по одному файлу на пользователя
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewStore создаёт хранилище списков в директории dir
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create ignore list directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Load загружает списки пользователя; отсутствие файла означает пустые списки
+func (s *Store) Load(userID int64) (List, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.read(userID)
+	if err != nil {
+		return List{}, err
+	}
+	return compile(stored)
+}
+
+// Add добавляет правило в список пользователя
+func (s *Store) Add(userID int64, kind Kind, raw string) (List, error) {
+	rule, err := ParseRule(raw)
+	if err != nil {
+		return List{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.read(userID)
+	if err != nil {
+		return List{}, err
+	}
+
+	target := &stored.Ignore
+	if kind == KindAllow {
+		target = &stored.Allow
+	}
+	if !contains(*target, rule.String()) {
+		*target = append(*target, rule.String())
+	}
+
+	if err := s.write(userID, stored); err != nil {
+		return List{}, err
+	}
+	return compile(stored)
+}
+
+// Remove удаляет правило из списка пользователя и сообщает, было ли оно найдено
+func (s *Store) Remove(userID int64, kind Kind, raw string) (List, bool, error) {
+	rule, err := ParseRule(raw)
+	if err != nil {
+		return List{}, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.read(userID)
+	if err != nil {
+		return List{}, false, err
+	}
+
+	target := &stored.Ignore
+	if kind == KindAllow {
+		target = &stored.Allow
+	}
+
+	removed := false
+	kept := (*target)[:0]
+	for _, r := range *target {
+		if r == rule.String() {
+			removed = true
+			continue
+		}
+		kept = append(kept, r)
+	}
+	*target = kept
+
+	if removed {
+		if err := s.write(userID, stored); err != nil {
+			return List{}, false, err
+		}
+	}
+
+	list, err := compile(stored)
+	return list, removed, err
+}
+
+// read читает файл пользователя
+func (s *Store) read(userID int64) (storedList, error) {
+	var stored storedList
+
+	data, err := os.ReadFile(s.path(userID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stored, nil
+		}
+		return stored, fmt.Errorf("failed to read ignore list: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return stored, fmt.Errorf("failed to unmarshal ignore list: %w", err)
+	}
+	return stored, nil
+}
+
+// write атомарно записывает файл пользователя
+func (s *Store) write(userID int64, stored storedList) error {
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ignore list: %w", err)
+	}
+
+	path := s.path(userID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write ignore list: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace ignore list: %w", err)
+	}
+	return nil
+}
+
+// path возвращает путь к файлу пользователя; ID хешируется, чтобы не хранить его в имени файла
+func (s *Store) path(userID int64) string {
+	h := sha256.Sum256([]byte(strconv.FormatInt(userID, 10)))
+	return filepath.Join(s.dir, fmt.Sprintf("%x.json", h[:16]))
+}
+
+// compile разбирает сохранённые правила
+func compile(stored storedList) (List, error) {
+	var list List
+	for _, raw := range stored.Ignore {
+		rule, err := ParseRule(raw)
+		if err != nil {
+			return List{}, err
+		}
+		list.Ignore = append(list.Ignore, rule)
+	}
+	for _, raw := range stored.Allow {
+		rule, err := ParseRule(raw)
+		if err != nil {
+			return List{}, err
+		}
+		list.Allow = append(list.Allow, rule)
+	}
+	return list, nil
+}
+
+// contains проверяет наличие строки в срезе
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
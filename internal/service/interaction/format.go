@@ -0,0 +1,90 @@
+package interaction
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// GraphML сериализует граф в формат GraphML для Gephi, yEd и аналогичных инструментов
+func (g Graph) GraphML() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	buf.WriteString(`  <key id="label" for="node" attr.name="label" attr.type="string"/>` + "\n")
+	buf.WriteString(`  <key id="channel" for="node" attr.name="channel" attr.type="boolean"/>` + "\n")
+	buf.WriteString(`  <key id="external" for="node" attr.name="external" attr.type="boolean"/>` + "\n")
+	buf.WriteString(`  <key id="kind" for="edge" attr.name="kind" attr.type="string"/>` + "\n")
+	buf.WriteString(`  <key id="weight" for="edge" attr.name="weight" attr.type="int"/>` + "\n")
+	buf.WriteString(`  <graph id="interactions" edgedefault="directed">` + "\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&buf, "    <node id=\"%s\">\n", escapeXML(n.ID))
+		fmt.Fprintf(&buf, "      <data key=\"label\">%s</data>\n", escapeXML(n.Label))
+		fmt.Fprintf(&buf, "      <data key=\"channel\">%t</data>\n", n.IsChannel)
+		fmt.Fprintf(&buf, "      <data key=\"external\">%t</data>\n", n.External)
+		buf.WriteString("    </node>\n")
+	}
+
+	for i, e := range g.Edges {
+		fmt.Fprintf(&buf, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, escapeXML(e.From), escapeXML(e.To))
+		fmt.Fprintf(&buf, "      <data key=\"kind\">%s</data>\n", e.Kind)
+		fmt.Fprintf(&buf, "      <data key=\"weight\">%d</data>\n", e.Weight)
+		buf.WriteString("    </edge>\n")
+	}
+
+	buf.WriteString("  </graph>\n")
+	buf.WriteString("</graphml>\n")
+
+	return buf.Bytes()
+}
+
+// DOT сериализует граф в формат Graphviz DOT
+func (g Graph) DOT() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("digraph interactions {\n")
+	buf.WriteString("  node [shape=ellipse];\n")
+
+	for _, n := range g.Nodes {
+		shape := "ellipse"
+		if n.IsChannel {
+			shape = "box"
+		}
+		style := "solid"
+		if n.External {
+			style = "dashed"
+		}
+		fmt.Fprintf(&buf, "  %s [label=%s, shape=%s, style=%s];\n", quoteDOT(n.ID), quoteDOT(n.Label), shape, style)
+	}
+
+	for _, e := range g.Edges {
+		style := "solid"
+		if e.Kind == EdgeForward {
+			style = "dashed"
+		}
+		fmt.Fprintf(&buf, "  %s -> %s [weight=%d, label=\"%d\", style=%s];\n",
+			quoteDOT(e.From), quoteDOT(e.To), e.Weight, e.Weight, style)
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+// escapeXML экранирует строку для текста и атрибутов XML
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// quoteDOT оборачивает идентификатор DOT в кавычки с экранированием
+func quoteDOT(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,219 @@
+package interaction
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Nikalively/telegram-export-parser/parser"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/metadata"
+)
+
+// EdgeKind тип связи между узлами графа
+type EdgeKind string
+
+const (
+	EdgeReply   EdgeKind = "reply"
+	EdgeForward EdgeKind = "forward"
+)
+
+// Node узел графа взаимодействий: участник чата или внешний источник пересылок
+type Node struct {
+	ID        string
+	Label     string
+	IsChannel bool
+	External  bool
+}
+
+// Edge взвешенная направленная связь: кто кому ответил или чьё сообщение переслал
+type Edge struct {
+	From   string
+	To     string
+	Kind   EdgeKind
+	Weight int
+}
+
+// Source источник пересланных в чат сообщений
+type Source struct {
+	ID        string
+	Name      string
+	IsChannel bool
+	External  bool
+	Count     int
+}
+
+// Graph результат анализа ответов и пересылок
+type Graph struct {
+	Nodes   []Node
+	Edges   []Edge
+	Sources []Source
+}
+
+// Empty сообщает, что в чате не найдено ни ответов, ни пересылок
+func (g Graph) Empty() bool {
+	return len(g.Edges) == 0
+}
+
+// TopEdges возвращает до limit самых тяжёлых связей заданного типа
+func (g Graph) TopEdges(kind EdgeKind, limit int) []Edge {
+	result := make([]Edge, 0, len(g.Edges))
+	for _, e := range g.Edges {
+		if e.Kind == kind {
+			result = append(result, e)
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// Labeler возвращает функцию, выдающую подпись узла по его ID; для неизвестных узлов возвращается ID.
+// Подписи индексируются один раз, поэтому функцию стоит получать до обхода рёбер.
+func (g Graph) Labeler() func(id string) string {
+	labels := make(map[string]string, len(g.Nodes))
+	for _, n := range g.Nodes {
+		labels[n.ID] = n.Label
+	}
+	return func(id string) string {
+		if label, ok := labels[id]; ok {
+			return label
+		}
+		return id
+	}
+}
+
+// Analyzer строит граф ответов и пересылок
+type Analyzer struct{}
+
+// New создаёт новый анализатор взаимодействий
+func New() *Analyzer {
+	return &Analyzer{}
+}
+
+type edgeKey struct {
+	from string
+	to   string
+	kind EdgeKind
+}
+
+// Analyze строит граф по событиям и метаданным сообщений
+func (a *Analyzer) Analyze(events []parser.Event, index metadata.Index) Graph {
+	nodes := make(map[string]*Node)
+	edges := make(map[edgeKey]int)
+	sources := make(map[string]*Source)
+
+	// Участники чата — все авторы сообщений
+	members := make(map[string]bool)
+	for _, event := range events {
+		if event.FromID != "" {
+			members[event.FromID] = true
+		}
+	}
+
+	addNode := func(id, label string) {
+		if _, exists := nodes[id]; exists {
+			return
+		}
+		if strings.TrimSpace(label) == "" {
+			label = id
+		}
+		nodes[id] = &Node{
+			ID:        id,
+			Label:     label,
+			IsChannel: strings.HasPrefix(id, "channel"),
+			External:  !members[id],
+		}
+	}
+
+	for _, event := range events {
+		if event.FromID == "" {
+			continue
+		}
+
+		msg, ok := index.Lookup(event)
+		if !ok {
+			continue
+		}
+
+		// Ответы: ребро от автора ответа к автору исходного сообщения
+		if msg.ReplyToID != 0 {
+			target, ok := index[metadata.Key{ChatID: event.ChatID, ID: msg.ReplyToID}]
+			if ok && target.FromID != "" && target.FromID != event.FromID {
+				addNode(event.FromID, msg.From)
+				addNode(target.FromID, target.From)
+				edges[edgeKey{from: event.FromID, to: target.FromID, kind: EdgeReply}]++
+			}
+		}
+
+		// Пересылки: ребро от источника к переславшему участнику
+		if msg.ForwardedFrom != "" || msg.ForwardedFromID != "" {
+			sourceID := msg.ForwardedFromID
+			if sourceID == "" {
+				sourceID = "name:" + msg.ForwardedFrom
+			}
+
+			addNode(event.FromID, msg.From)
+			addNode(sourceID, msg.ForwardedFrom)
+			edges[edgeKey{from: sourceID, to: event.FromID, kind: EdgeForward}]++
+
+			src, exists := sources[sourceID]
+			if !exists {
+				src = &Source{
+					ID:        sourceID,
+					Name:      msg.ForwardedFrom,
+					IsChannel: strings.HasPrefix(sourceID, "channel"),
+					External:  !members[sourceID],
+				}
+				sources[sourceID] = src
+			}
+			src.Count++
+		}
+	}
+
+	return buildGraph(nodes, edges, sources)
+}
+
+// buildGraph преобразует карты в отсортированные срезы для стабильного вывода
+func buildGraph(nodes map[string]*Node, edges map[edgeKey]int, sources map[string]*Source) Graph {
+	g := Graph{
+		Nodes:   make([]Node, 0, len(nodes)),
+		Edges:   make([]Edge, 0, len(edges)),
+		Sources: make([]Source, 0, len(sources)),
+	}
+
+	for _, n := range nodes {
+		g.Nodes = append(g.Nodes, *n)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+
+	for k, w := range edges {
+		g.Edges = append(g.Edges, Edge{From: k.from, To: k.to, Kind: k.kind, Weight: w})
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].Weight != g.Edges[j].Weight {
+			return g.Edges[i].Weight > g.Edges[j].Weight
+		}
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		if g.Edges[i].To != g.Edges[j].To {
+			return g.Edges[i].To < g.Edges[j].To
+		}
+		return g.Edges[i].Kind < g.Edges[j].Kind
+	})
+
+	for _, s := range sources {
+		g.Sources = append(g.Sources, *s)
+	}
+	sort.Slice(g.Sources, func(i, j int) bool {
+		if g.Sources[i].Count != g.Sources[j].Count {
+			return g.Sources[i].Count > g.Sources[j].Count
+		}
+		return g.Sources[i].ID < g.Sources[j].ID
+	})
+
+	return g
+}
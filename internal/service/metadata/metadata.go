@@ -0,0 +1,201 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Nikalively/telegram-export-parser/parser"
+)
+
+// Типы сообщений в экспорте Telegram
+const (
+	TypeMessage = "message"
+	TypeService = "service"
+)
+
+// Message хранит поля сообщения из экспорта Telegram, которые не попадают в parser.Event
+type Message struct {
+	ID              int64
+	ChatID          int64
+	Type            string
+	From            string
+	FromID          string
+	ReplyToID       int64
+	ForwardedFrom   string
+	ForwardedFromID string
+	ViaBot          string
+	Author          string
+	Actor           string
+	ActorID         string
+	Action          string
+	Date            time.Time
+
+	// InlineButtons сообщение содержит inline-клавиатуру, которую могут прикрепить только боты
+	InlineButtons bool
+}
+
+// Key однозначно определяет сообщение в пределах всех загруженных экспортов
+type Key struct {
+	ChatID int64
+	ID     int64
+}
+
+// Index хранит метаданные сообщений по ключу чат + ID сообщения
+type Index map[Key]Message
+
+// rawExport повторяет корневую структуру JSON экспорта Telegram
+type rawExport struct {
+	ID       int64        `json:"id"`
+	Messages []rawMessage `json:"messages"`
+}
+
+// rawMessage содержит только поля, нужные для метаданных
+type rawMessage struct {
+	ID              int64           `json:"id"`
+	Type            string          `json:"type"`
+	From            string          `json:"from"`
+	FromID          string          `json:"from_id"`
+	ReplyToID       int64           `json:"reply_to_message_id"`
+	ForwardedFrom   string          `json:"forwarded_from"`
+	ForwardedFromID interface{}     `json:"forwarded_from_id"`
+	ViaBot          string          `json:"via_bot"`
+	InlineButtons   json.RawMessage `json:"inline_bot_buttons"`
+	Author          string          `json:"author"`
+	Actor           string          `json:"actor"`
+	ActorID         string          `json:"actor_id"`
+	Action          string          `json:"action"`
+	Date            string          `json:"date"`
+}
+
+// Parse извлекает метаданные сообщений из содержимого файла экспорта.
+// Для форматов без метаданных (HTML) возвращается пустой индекс.
+func Parse(data []byte, filename string) (Index, error) {
+	index := make(Index)
+
+	if strings.ToLower(filepath.Ext(filename)) != ".json" {
+		return index, nil
+	}
+
+	var export rawExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	for _, msg := range export.Messages {
+		index[Key{ChatID: export.ID, ID: msg.ID}] = Message{
+			ID:              msg.ID,
+			ChatID:          export.ID,
+			Type:            msg.Type,
+			From:            msg.From,
+			FromID:          msg.FromID,
+			ReplyToID:       msg.ReplyToID,
+			ForwardedFrom:   msg.ForwardedFrom,
+			ForwardedFromID: stringID(msg.ForwardedFromID),
+			ViaBot:          msg.ViaBot,
+			InlineButtons:   len(msg.InlineButtons) > 0 && string(msg.InlineButtons) != "null",
+			Author:          msg.Author,
+			Actor:           msg.Actor,
+			ActorID:         msg.ActorID,
+			Action:          msg.Action,
+			Date:            parseDate(msg.Date),
+		}
+	}
+
+	return index, nil
+}
+
+// Merge объединяет несколько индексов в один
+func Merge(indexes ...Index) Index {
+	result := make(Index)
+	for _, index := range indexes {
+		for key, msg := range index {
+			result[key] = msg
+		}
+	}
+	return result
+}
+
+// Lookup возвращает метаданные для события парсера
+func (idx Index) Lookup(event parser.Event) (Message, bool) {
+	msg, ok := idx[Key{ChatID: event.ChatID, ID: event.ID}]
+	return msg, ok
+}
+
+// ServiceEvents превращает служебные сообщения (вступления, закрепы и т.п.) в события,
+// автором которых считается инициатор действия. Парсер такие сообщения пропускает.
+func (idx Index) ServiceEvents() []parser.Event {
+	var events []parser.Event
+	for _, msg := range idx {
+		if msg.Type != TypeService || msg.ActorID == "" || msg.Date.IsZero() {
+			continue
+		}
+		events = append(events, parser.Event{
+			ID:     msg.ID,
+			FromID: msg.ActorID,
+			Date:   msg.Date,
+			ChatID: msg.ChatID,
+		})
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Date.Before(events[j].Date)
+	})
+
+	return events
+}
+
+// Bots возвращает from_id авторов, которые являются ботами. Экспорт не содержит username
+// авторов, поэтому бот определяется по косвенным признакам: сообщение с inline-клавиатурой
+// или отображаемое имя автора, совпадающее с username бота из via_bot.
+func (idx Index) Bots() map[string]bool {
+	viaBots := make(map[string]bool)
+	for _, msg := range idx {
+		if msg.ViaBot != "" {
+			viaBots[botNameKey(msg.ViaBot)] = true
+		}
+	}
+
+	bots := make(map[string]bool)
+	for _, msg := range idx {
+		if msg.Type != TypeMessage || msg.FromID == "" {
+			continue
+		}
+		if msg.InlineButtons || viaBots[botNameKey(msg.From)] {
+			bots[msg.FromID] = true
+		}
+	}
+	return bots
+}
+
+// botNameKey приводит username или отображаемое имя к виду для сравнения: "@Quiz_Bot" и "Quiz Bot" совпадают
+func botNameKey(name string) string {
+	return strings.NewReplacer("@", "", " ", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// parseDate разбирает дату сообщения в форматах, которые встречаются в экспортах
+func parseDate(value string) time.Time {
+	date, err := time.Parse("2006-01-02T15:04:05", value)
+	if err != nil {
+		date, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}
+		}
+	}
+	return date
+}
+
+// stringID приводит ID из экспорта к строке: старые экспорты хранят числа, новые — строки вида "user123"
+func stringID(v interface{}) string {
+	switch id := v.(type) {
+	case string:
+		return id
+	case float64:
+		return fmt.Sprintf("%.0f", id)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,143 @@
+package participant
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Nikalively/telegram-export-parser/parser"
+)
+
+// ContactType тип контактных данных
+type ContactType string
+
+const (
+	ContactPhone ContactType = "phone"
+	ContactEmail ContactType = "email"
+)
+
+var (
+	phoneRe = regexp.MustCompile(`\+?\d[\d\s\-().]{8,}\d`)
+	emailRe = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+// Contact контакт, опубликованный участником чата
+type Contact struct {
+	Type   ContactType
+	Value  string
+	UserID string
+	Count  int
+}
+
+type contactKey struct {
+	kind   ContactType
+	value  string
+	userID string
+}
+
+// contactCollector собирает телефоны и email из сообщений
+type contactCollector struct {
+	contacts map[contactKey]int
+}
+
+// newContactCollector создаёт пустой сборщик
+func newContactCollector() *contactCollector {
+	return &contactCollector{contacts: make(map[contactKey]int)}
+}
+
+// add учитывает контакты одного сообщения, привязывая их к автору
+func (c *contactCollector) add(event parser.Event) {
+	found := make(map[contactKey]bool)
+
+	addPhone := func(raw string) {
+		if phone, ok := NormalizePhone(raw); ok {
+			found[contactKey{kind: ContactPhone, value: phone, userID: event.FromID}] = true
+		}
+	}
+	addEmail := func(raw string) {
+		if email, ok := NormalizeEmail(raw); ok {
+			found[contactKey{kind: ContactEmail, value: email, userID: event.FromID}] = true
+		}
+	}
+
+	for _, entity := range event.Entities {
+		switch entity.Type {
+		case "phone":
+			addPhone(entity.Text)
+		case "email":
+			addEmail(entity.Text)
+		}
+	}
+	for _, match := range phoneRe.FindAllString(event.Text, -1) {
+		addPhone(match)
+	}
+	for _, match := range emailRe.FindAllString(event.Text, -1) {
+		addEmail(match)
+	}
+
+	for key := range found {
+		c.contacts[key]++
+	}
+}
+
+// stats возвращает контакты, сгруппированные по типу и значению. Контакты авторов,
+// для которых keep возвращает false, не попадают в результат.
+func (c *contactCollector) stats(keep func(userID string) bool) []Contact {
+	result := make([]Contact, 0, len(c.contacts))
+	for key, count := range c.contacts {
+		if !keep(key.userID) {
+			continue
+		}
+		result = append(result, Contact{Type: key.kind, Value: key.value, UserID: key.userID, Count: count})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Type != result[j].Type {
+			return result[i].Type > result[j].Type
+		}
+		if result[i].Value != result[j].Value {
+			return result[i].Value < result[j].Value
+		}
+		return result[i].UserID < result[j].UserID
+	})
+	return result
+}
+
+// NormalizePhone приводит номер телефона к формату E.164.
+// Номера без кода страны принимаются только в российском формате 8XXXXXXXXXX.
+func NormalizePhone(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	plus := strings.HasPrefix(raw, "+")
+
+	var digits strings.Builder
+	for _, c := range raw {
+		if c >= '0' && c <= '9' {
+			digits.WriteRune(c)
+		}
+	}
+	d := digits.String()
+
+	switch {
+	case plus:
+	case len(d) == 11 && d[0] == '8':
+		d = "7" + d[1:]
+	case len(d) == 11 && d[0] == '7':
+	default:
+		return "", false
+	}
+
+	// E.164: до 15 цифр, код страны не начинается с нуля
+	if len(d) < 10 || len(d) > 15 || d[0] == '0' {
+		return "", false
+	}
+	return "+" + d, true
+}
+
+// NormalizeEmail приводит email к нижнему регистру и проверяет базовый формат
+func NormalizeEmail(raw string) (string, bool) {
+	email := strings.ToLower(strings.Trim(strings.TrimSpace(raw), ".,;:<>()[]"))
+	if !emailRe.MatchString(email) || emailRe.FindString(email) != email {
+		return "", false
+	}
+	return email, true
+}
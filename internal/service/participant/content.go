@@ -0,0 +1,219 @@
+package participant
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Nikalively/telegram-export-parser/parser"
+)
+
+// topHashtagUsers количество самых активных авторов, сохраняемых для каждого хэштега
+const topHashtagUsers = 3
+
+var (
+	hashtagRe = regexp.MustCompile(`#[\p{L}\p{N}_]+`)
+	urlRe     = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"]+`)
+)
+
+// trackingParams параметры запроса, которые не влияют на содержимое страницы
+var trackingParams = map[string]bool{
+	"fbclid":    true,
+	"gclid":     true,
+	"yclid":     true,
+	"dclid":     true,
+	"igshid":    true,
+	"mc_cid":    true,
+	"mc_eid":    true,
+	"_openstat": true,
+	"ref_src":   true,
+	"si":        true,
+}
+
+// UserCount количество упоминаний элемента конкретным автором
+type UserCount struct {
+	UserID string
+	Count  int
+}
+
+// HashtagStat статистика по хэштегу
+type HashtagStat struct {
+	Tag      string
+	Count    int
+	TopUsers []UserCount
+}
+
+// LinkStat статистика по нормализованной ссылке
+type LinkStat struct {
+	URL    string
+	Domain string
+	Count  int
+}
+
+// DomainStat статистика по домену
+type DomainStat struct {
+	Domain string
+	Count  int
+}
+
+// contentCollector собирает хэштеги, ссылки и домены из сообщений
+type contentCollector struct {
+	hashtags     map[string]int
+	hashtagUsers map[string]map[string]int
+	links        map[string]int
+	domains      map[string]int
+}
+
+// newContentCollector создаёт пустой сборщик
+func newContentCollector() *contentCollector {
+	return &contentCollector{
+		hashtags:     make(map[string]int),
+		hashtagUsers: make(map[string]map[string]int),
+		links:        make(map[string]int),
+		domains:      make(map[string]int),
+	}
+}
+
+// add учитывает хэштеги и ссылки одного сообщения; повторы внутри сообщения считаются один раз
+func (c *contentCollector) add(event parser.Event) {
+	tags := make(map[string]bool)
+	links := make(map[string]bool)
+
+	for _, link := range urlRe.FindAllString(event.Text, -1) {
+		links[link] = true
+	}
+	// Фрагменты ссылок (#section) не считаем хэштегами
+	for _, tag := range hashtagRe.FindAllString(urlRe.ReplaceAllString(event.Text, " "), -1) {
+		tags[strings.ToLower(tag)] = true
+	}
+
+	for _, entity := range event.Entities {
+		switch entity.Type {
+		case "hashtag":
+			tags[strings.ToLower(entity.Text)] = true
+		case "link", "url":
+			links[entity.Text] = true
+		}
+	}
+
+	for tag := range tags {
+		c.hashtags[tag]++
+		if event.FromID != "" {
+			if c.hashtagUsers[tag] == nil {
+				c.hashtagUsers[tag] = make(map[string]int)
+			}
+			c.hashtagUsers[tag][event.FromID]++
+		}
+	}
+
+	normalized := make(map[string]string)
+	for link := range links {
+		if u, domain, ok := NormalizeURL(link); ok {
+			normalized[u] = domain
+		}
+	}
+	for u, domain := range normalized {
+		c.links[u]++
+		c.domains[domain]++
+	}
+}
+
+// hashtagStats возвращает хэштеги по убыванию частоты
+func (c *contentCollector) hashtagStats() []HashtagStat {
+	stats := make([]HashtagStat, 0, len(c.hashtags))
+	for tag, count := range c.hashtags {
+		users := make([]UserCount, 0, len(c.hashtagUsers[tag]))
+		for id, n := range c.hashtagUsers[tag] {
+			users = append(users, UserCount{UserID: id, Count: n})
+		}
+		sort.Slice(users, func(i, j int) bool {
+			if users[i].Count != users[j].Count {
+				return users[i].Count > users[j].Count
+			}
+			return users[i].UserID < users[j].UserID
+		})
+		if len(users) > topHashtagUsers {
+			users = users[:topHashtagUsers]
+		}
+		stats = append(stats, HashtagStat{Tag: tag, Count: count, TopUsers: users})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Tag < stats[j].Tag
+	})
+	return stats
+}
+
+// linkStats возвращает ссылки по убыванию частоты
+func (c *contentCollector) linkStats() []LinkStat {
+	stats := make([]LinkStat, 0, len(c.links))
+	for u, count := range c.links {
+		_, domain, _ := NormalizeURL(u)
+		stats = append(stats, LinkStat{URL: u, Domain: domain, Count: count})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].URL < stats[j].URL
+	})
+	return stats
+}
+
+// domainStats возвращает домены по убыванию частоты
+func (c *contentCollector) domainStats() []DomainStat {
+	stats := make([]DomainStat, 0, len(c.domains))
+	for domain, count := range c.domains {
+		stats = append(stats, DomainStat{Domain: domain, Count: count})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Domain < stats[j].Domain
+	})
+	return stats
+}
+
+// NormalizeURL приводит ссылку к каноническому виду: схема и хост в нижнем регистре,
+// без фрагмента, utm-меток и других трекинговых параметров. Возвращает ссылку и домен без "www.".
+func NormalizeURL(raw string) (string, string, bool) {
+	raw = strings.TrimRight(strings.TrimSpace(raw), ".,;:!?)]}»'\"")
+	if raw == "" {
+		return "", "", false
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", "", false
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") || trackingParams[strings.ToLower(key)] {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	if u.Path == "/" {
+		u.Path = ""
+	}
+
+	domain := strings.TrimPrefix(u.Hostname(), "www.")
+	return u.String(), domain, true
+}
@@ -1,59 +1,145 @@
 package participant
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
 	"github.com/Nikalively/telegram-export-parser/parser"
 	"github.com/lintenved/tg-exporter/exporter"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/ignorelist"
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/metadata"
 )
 
+// tracer создаёт спаны этапов извлечения
+var tracer = otel.Tracer("github.com/MaxFando/tg-export-chat-analyzer/internal/service/participant")
+
 // Extractor интерфейс для извлечения участников из событий
 type Extractor interface {
-	Extract(events []parser.Event) (exporter.ParticipantsResult, error)
+	Extract(ctx context.Context, events []parser.Event, index metadata.Index) (Result, error)
+}
+
+// Result результат извлечения участников с типами аккаунтов
+type Result struct {
+	exporter.ParticipantsResult
+
+	// Kinds тип аккаунта по ID участника или упоминания (exporter.Participant.ID).
+	// Содержит и авторов, не попавших в результат, например каналы и анонимных админов.
+	Kinds map[string]Kind
+
+	Hashtags []HashtagStat
+	Links    []LinkStat
+	Domains  []DomainStat
+
+	// Contacts заполняется только при включённой опции WithContacts
+	Contacts []Contact
+}
+
+// KindOf возвращает тип аккаунта участника
+func (r Result) KindOf(p exporter.Participant) Kind {
+	if kind, ok := r.Kinds[p.ID]; ok {
+		return kind
+	}
+	return KindUser
 }
 
 // ParticipantExtractor реализует интерфейс Extractor
 type ParticipantExtractor struct {
-	// поля для будущего расширения конфигурации
+	excludeBots     bool
+	minMessages     int
+	includeMentions bool
+	excludeUsers    []string
+	caseSensitive   bool
+	ignoreList      ignorelist.List
+	contacts        bool
+	includeChannels bool
 }
 
 // New создаёт новый экстрактор участников
-func New() *ParticipantExtractor {
-	return &ParticipantExtractor{}
+func New(opts ...Option) *ParticipantExtractor {
+	pe := &ParticipantExtractor{
+		minMessages:     1,
+		includeMentions: true,
+	}
+	for _, opt := range opts {
+		opt(pe)
+	}
+	return pe
 }
 
 // Extract извлекает участников и упоминания из событий
-func (pe *ParticipantExtractor) Extract(events []parser.Event) (exporter.ParticipantsResult, error) {
+func (pe *ParticipantExtractor) Extract(ctx context.Context, events []parser.Event, index metadata.Index) (Result, error) {
+	_, span := tracer.Start(ctx, "participant.Extract",
+		trace.WithAttributes(attribute.Int("events.count", len(events))))
+	defer span.End()
+
 	// Карты для дедупликации
 	participantMap := make(map[string]*exporter.Participant)
 	mentionMap := make(map[string]*exporter.Participant)
 	channelSet := make(map[string]bool)
+	kinds := make(map[string]Kind)
+	messageCounts := make(map[string]int)
+	content := newContentCollector()
+	contacts := newContactCollector()
+	bots := index.Bots()
 
 	// Обрабатываем каждое событие
 	for _, event := range events {
-		// Добавляем автора как участника
-		if event.FromID != "" && !strings.HasPrefix(event.FromID, "channel") {
-			key := strings.ToLower(event.FromID)
-			if _, exists := participantMap[key]; !exists {
-				participantMap[key] = &exporter.Participant{
-					ID:        event.FromID,
-					Username:  extractUsername(event.FromID),
+		msg, _ := index.Lookup(event)
+		content.add(event)
+		if pe.contacts {
+			contacts.add(event)
+		}
+
+		// Добавляем автора как участника. Каналы и анонимные админы попадают только в Kinds,
+		// если их включение не запрошено явно
+		if event.FromID != "" {
+			kind := classifyAuthor(event, bots)
+			kinds[event.FromID] = kind
+
+			if pe.includeChannels || !kind.isChannel() {
+				key := pe.key(event.FromID)
+				messageCounts[key]++
+				if _, exists := participantMap[key]; !exists {
+					participantMap[key] = &exporter.Participant{
+						ID:        event.FromID,
+						Username:  extractUsername(event.FromID),
+						IsDeleted: false,
+					}
+				}
+			}
+		}
+
+		// Бот, через которого отправлено сообщение, считаем упомянутым
+		if via := strings.TrimLeft(msg.ViaBot, "@"); via != "" {
+			key := pe.key(via)
+			if _, exists := mentionMap[key]; !exists {
+				mentionMap[key] = &exporter.Participant{
+					ID:        via,
+					Username:  via,
 					IsDeleted: false,
 				}
 			}
+			kinds[mentionMap[key].ID] = KindBot
 		}
 
 		// Извлекаем упоминания из текста сообщения
 		mentions := extractMentions(event.Text)
 		for _, mention := range mentions {
-			key := strings.ToLower(mention)
+			key := pe.key(mention)
 			if _, exists := mentionMap[key]; !exists {
 				mentionMap[key] = &exporter.Participant{
 					ID:        mention,
 					Username:  mention,
 					IsDeleted: false,
 				}
+				if _, known := kinds[mention]; !known {
+					kinds[mention] = classifyMention(mention)
+				}
 			}
 		}
 
@@ -62,13 +148,16 @@ func (pe *ParticipantExtractor) Extract(events []parser.Event) (exporter.Partici
 			if entity.Type == "mention" {
 				mention := strings.TrimLeft(entity.Text, "@")
 				if mention != "" {
-					key := strings.ToLower(mention)
+					key := pe.key(mention)
 					if _, exists := mentionMap[key]; !exists {
 						mentionMap[key] = &exporter.Participant{
 							ID:        mention,
 							Username:  mention,
 							IsDeleted: false,
 						}
+						if _, known := kinds[mention]; !known {
+							kinds[mention] = classifyMention(mention)
+						}
 					}
 				}
 			}
@@ -80,6 +169,29 @@ func (pe *ParticipantExtractor) Extract(events []parser.Event) (exporter.Partici
 		}
 	}
 
+	// Исключаем ботов, если это запрошено
+	if pe.excludeBots {
+		excludeKind(participantMap, kinds, KindBot)
+		excludeKind(mentionMap, kinds, KindBot)
+	}
+
+	// Исключаем малоактивных авторов
+	for key := range participantMap {
+		if messageCounts[key] < pe.minMessages {
+			delete(participantMap, key)
+		}
+	}
+
+	// Исключаем пользователей из списка исключений
+	pe.excludeListed(participantMap)
+	pe.excludeListed(mentionMap)
+	pe.excludeIgnored(participantMap)
+	pe.excludeIgnored(mentionMap)
+
+	if !pe.includeMentions {
+		clear(mentionMap)
+	}
+
 	// Фильтруем удалённые аккаунты и пустые значения
 	participants := filterParticipants(participantMap)
 	mentions := filterParticipants(mentionMap)
@@ -90,13 +202,88 @@ func (pe *ParticipantExtractor) Extract(events []parser.Event) (exporter.Partici
 		channels = append(channels, ch)
 	}
 
-	return exporter.ParticipantsResult{
-		Participants: participants,
-		Mentions:     mentions,
-		Channels:     channels,
+	// Контакты показываются только для авторов, оставшихся участниками: исключённые,
+	// игнорируемые, боты и скрытые каналы не раскрывают свои телефоны и email
+	var contactStats []Contact
+	if pe.contacts {
+		contactStats = contacts.stats(func(userID string) bool {
+			_, ok := participantMap[pe.key(userID)]
+			return ok
+		})
+	}
+
+	span.SetAttributes(
+		attribute.Int("participants.count", len(participants)),
+		attribute.Int("mentions.count", len(mentions)),
+		attribute.Int("channels.count", len(channels)),
+	)
+
+	return Result{
+		ParticipantsResult: exporter.ParticipantsResult{
+			Participants: participants,
+			Mentions:     mentions,
+			Channels:     channels,
+		},
+		Kinds:    kinds,
+		Hashtags: content.hashtagStats(),
+		Links:    content.linkStats(),
+		Domains:  content.domainStats(),
+		Contacts: contactStats,
 	}, nil
 }
 
+// key возвращает ключ дедупликации с учётом чувствительности к регистру
+func (pe *ParticipantExtractor) key(s string) string {
+	if pe.caseSensitive {
+		return s
+	}
+	return strings.ToLower(s)
+}
+
+// excludeListed удаляет из карты участников, перечисленных в WithExcludeUsers
+func (pe *ParticipantExtractor) excludeListed(pMap map[string]*exporter.Participant) {
+	if len(pe.excludeUsers) == 0 {
+		return
+	}
+
+	excluded := make(map[string]bool, len(pe.excludeUsers))
+	for _, u := range pe.excludeUsers {
+		excluded[pe.key(u)] = true
+	}
+
+	for key, p := range pMap {
+		if excluded[pe.key(normalizeName(p.ID))] || excluded[pe.key(normalizeName(p.Username))] {
+			delete(pMap, key)
+		}
+	}
+}
+
+// excludeIgnored удаляет из карты участников, подходящих под списки игнорирования
+func (pe *ParticipantExtractor) excludeIgnored(pMap map[string]*exporter.Participant) {
+	if pe.ignoreList.IsEmpty() {
+		return
+	}
+	for key, p := range pMap {
+		if pe.ignoreList.Excludes(p.ID, p.Username) {
+			delete(pMap, key)
+		}
+	}
+}
+
+// normalizeName убирает @ и пробелы вокруг имени
+func normalizeName(name string) string {
+	return strings.TrimPrefix(strings.TrimSpace(name), "@")
+}
+
+// excludeKind удаляет из карты участников аккаунты заданного типа
+func excludeKind(pMap map[string]*exporter.Participant, kinds map[string]Kind, kind Kind) {
+	for key, p := range pMap {
+		if kinds[p.ID] == kind {
+			delete(pMap, key)
+		}
+	}
+}
+
 // extractUsername извлекает username из ID или текста
 func extractUsername(id string) string {
 	// Если это уже username с @, убираем его
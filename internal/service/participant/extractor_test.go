@@ -0,0 +1,85 @@
+package participant
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/Nikalively/telegram-export-parser/parser"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/metadata"
+)
+
+// botExport фрагмент экспорта Telegram Desktop: бот отвечает в группе сообщением с кнопками,
+// второй бот пишет сам и до этого использовался как inline-бот
+const botExport = `{
+  "name": "Test group",
+  "type": "private_supergroup",
+  "id": 1234567890,
+  "messages": [
+    {
+      "id": 1,
+      "type": "message",
+      "date": "2024-03-01T10:00:00",
+      "from": "Ivan",
+      "from_id": "user111",
+      "text": "/start"
+    },
+    {
+      "id": 2,
+      "type": "message",
+      "date": "2024-03-01T10:00:01",
+      "from": "Helper",
+      "from_id": "user222",
+      "text": "Choose an option",
+      "inline_bot_buttons": [[{"type": "callback", "text": "Yes", "data": "eWVz"}]]
+    },
+    {
+      "id": 3,
+      "type": "message",
+      "date": "2024-03-01T10:01:00",
+      "from": "Ivan",
+      "from_id": "user111",
+      "via_bot": "@quiz_bot",
+      "text": "Quiz"
+    },
+    {
+      "id": 4,
+      "type": "message",
+      "date": "2024-03-01T10:02:00",
+      "from": "Quiz Bot",
+      "from_id": "user333",
+      "text": "Round 1"
+    }
+  ]
+}`
+
+func TestExtractExcludesBotAuthors(t *testing.T) {
+	data := []byte(botExport)
+	events, err := parser.ParseFile(bytes.NewReader(data), "result.json")
+	if err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+	index, err := metadata.Parse(data, "result.json")
+	if err != nil {
+		t.Fatalf("failed to parse metadata: %v", err)
+	}
+
+	result, err := New(WithExcludeBots(false)).Extract(context.Background(), events, index)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	for id, want := range map[string]Kind{"user111": KindUser, "user222": KindBot, "user333": KindBot} {
+		if got := result.Kinds[id]; got != want {
+			t.Errorf("Kinds[%s] = %q, want %q", id, got, want)
+		}
+	}
+
+	result, err = New(WithExcludeBots(true)).Extract(context.Background(), events, index)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(result.Participants) != 1 || result.Participants[0].ID != "user111" {
+		t.Errorf("participants = %+v, want only user111", result.Participants)
+	}
+}
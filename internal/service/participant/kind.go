@@ -0,0 +1,99 @@
+package participant
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Nikalively/telegram-export-parser/parser"
+)
+
+// Kind тип аккаунта участника
+type Kind string
+
+const (
+	KindUser           Kind = "user"
+	KindBot            Kind = "bot"
+	KindChannel        Kind = "channel"
+	KindAnonymousAdmin Kind = "anonymous_admin"
+)
+
+// Labels подписи типов аккаунтов на языке пользователя
+type Labels struct {
+	User           string
+	Bot            string
+	Channel        string
+	AnonymousAdmin string
+}
+
+// DefaultLabels подписи на русском языке
+var DefaultLabels = Labels{
+	User:           "пользователь",
+	Bot:            "бот",
+	Channel:        "канал",
+	AnonymousAdmin: "анонимный админ",
+}
+
+// OrDefault возвращает подписи по умолчанию, если подписи не заданы
+func (l Labels) OrDefault() Labels {
+	if l == (Labels{}) {
+		return DefaultLabels
+	}
+	return l
+}
+
+// Format возвращает подпись типа аккаунта для пользовательского вывода с заданными подписями
+func (k Kind) Format(labels Labels) string {
+	labels = labels.OrDefault()
+	switch k {
+	case KindBot:
+		return labels.Bot
+	case KindChannel:
+		return labels.Channel
+	case KindAnonymousAdmin:
+		return labels.AnonymousAdmin
+	default:
+		return labels.User
+	}
+}
+
+// isChannel сообщает, что аккаунт пишет от имени канала или группы, а не человека
+func (k Kind) isChannel() bool {
+	return k == KindChannel || k == KindAnonymousAdmin
+}
+
+// classifyAuthor определяет тип автора сообщения по его ID и набору ботов из метаданных
+// экспорта (metadata.Index.Bots). Отображаемое имя само по себе не учитывается:
+// на "bot" обязаны оканчиваться только username ботов, а имя "Talbot" может быть у человека.
+func classifyAuthor(event parser.Event, bots map[string]bool) Kind {
+	if strings.HasPrefix(event.FromID, "channel") {
+		// Анонимные админы пишут от имени самой группы
+		if event.FromID == "channel"+strconv.FormatInt(event.ChatID, 10) {
+			return KindAnonymousAdmin
+		}
+		return KindChannel
+	}
+
+	if bots[event.FromID] {
+		return KindBot
+	}
+
+	if username := extractUsername(event.FromID); username != "" && isBotName(username) {
+		return KindBot
+	}
+
+	return KindUser
+}
+
+// classifyMention определяет тип упомянутого аккаунта по username
+func classifyMention(username string) Kind {
+	if isBotName(username) {
+		return KindBot
+	}
+	return KindUser
+}
+
+// isBotName проверяет, похоже ли имя на бота: username ботов в Telegram обязаны оканчиваться на "bot"
+func isBotName(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "@")))
+	return strings.HasSuffix(name, "bot")
+}
@@ -0,0 +1,72 @@
+package participant
+
+import "github.com/MaxFando/tg-export-chat-analyzer/internal/service/ignorelist"
+
+// Option настраивает ParticipantExtractor
+type Option func(*ParticipantExtractor)
+
+// WithExcludeBots исключает ботов из участников и упоминаний
+func WithExcludeBots(exclude bool) Option {
+	return func(pe *ParticipantExtractor) {
+		pe.excludeBots = exclude
+	}
+}
+
+// WithIncludeChannels включает в участников каналы и анонимных админов, пишущих от имени группы.
+// По умолчанию они не считаются участниками и видны только в Result.Kinds.
+func WithIncludeChannels(include bool) Option {
+	return func(pe *ParticipantExtractor) {
+		pe.includeChannels = include
+	}
+}
+
+// WithMinMessages оставляет только авторов, написавших не меньше n сообщений
+func WithMinMessages(n int) Option {
+	return func(pe *ParticipantExtractor) {
+		if n < 1 {
+			n = 1
+		}
+		pe.minMessages = n
+	}
+}
+
+// WithIncludeMentions включает или выключает сбор упоминаний
+func WithIncludeMentions(include bool) Option {
+	return func(pe *ParticipantExtractor) {
+		pe.includeMentions = include
+	}
+}
+
+// WithExcludeUsers исключает из результата пользователей по username или ID (с @ или без).
+// Сравнение учитывает WithCaseSensitive независимо от порядка опций.
+func WithExcludeUsers(users ...string) Option {
+	return func(pe *ParticipantExtractor) {
+		for _, u := range users {
+			if u = normalizeName(u); u != "" {
+				pe.excludeUsers = append(pe.excludeUsers, u)
+			}
+		}
+	}
+}
+
+// WithCaseSensitive включает учёт регистра при дедупликации и сравнении имён
+func WithCaseSensitive(sensitive bool) Option {
+	return func(pe *ParticipantExtractor) {
+		pe.caseSensitive = sensitive
+	}
+}
+
+// WithIgnoreList исключает участников и упоминания по спискам игнорирования с учётом разрешающих правил
+func WithIgnoreList(list ignorelist.List) Option {
+	return func(pe *ParticipantExtractor) {
+		pe.ignoreList = list
+	}
+}
+
+// WithContacts включает извлечение телефонов и email участников.
+// Это персональные данные, поэтому по умолчанию извлечение выключено.
+func WithContacts(enabled bool) Option {
+	return func(pe *ParticipantExtractor) {
+		pe.contacts = enabled
+	}
+}
@@ -0,0 +1,410 @@
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Нарушения лимитов
+const (
+	ViolationCommandRate  = "command_rate"
+	ViolationProcessQuota = "process_quota"
+	ViolationUploadQuota  = "upload_quota"
+	ViolationBanned       = "banned"
+)
+
+const (
+	day = 24 * time.Hour
+
+	// DefaultCommandBurst команд подряд без паузы
+	DefaultCommandBurst = 10
+
+	// limiterSweepInterval период удаления ограничителей неактивных пользователей
+	limiterSweepInterval = 10 * time.Minute
+)
+
+// Limits лимиты одного пользователя; нулевое значение отключает соответствующий лимит
+type Limits struct {
+	// ProcessPerHour и ProcessPerDay обработок /process за скользящий час и сутки
+	ProcessPerHour int
+	ProcessPerDay  int
+	// UploadBytesPerDay байт в загруженных файлах за скользящие сутки
+	UploadBytesPerDay int64
+	// CommandsPerMinute и CommandBurst частота команд и файлов
+	CommandsPerMinute float64
+	CommandBurst      int
+	// BanAfter нарушений за BanWindow, после которых пользователь блокируется на BanDuration
+	BanAfter    int
+	BanWindow   time.Duration
+	BanDuration time.Duration
+}
+
+// Decision результат проверки лимита
+type Decision struct {
+	// Allowed действие разрешено и учтено
+	Allowed bool
+	// Violation причина отказа
+	Violation string
+	// RetryAfter через сколько действие станет доступно
+	RetryAfter time.Duration
+	// Notify первый отказ после разрешённого действия: о нём стоит сообщить пользователю,
+	// о повторных — нет, чтобы не отвечать на каждое сообщение
+	Notify bool
+	// Banned пользователь заблокирован этим нарушением
+	Banned bool
+}
+
+// Ban временная блокировка пользователя
+type Ban struct {
+	UserID int64
+	Until  time.Time
+}
+
+// upload загруженный файл
+type upload struct {
+	At    time.Time `json:"at"`
+	Bytes int64     `json:"bytes"`
+}
+
+// userState счётчики пользователя, сохраняемые на диск
+type userState struct {
+	Processes   []time.Time `json:"processes,omitempty"`
+	Uploads     []upload    `json:"uploads,omitempty"`
+	Violations  []time.Time `json:"violations,omitempty"`
+	BannedUntil time.Time   `json:"banned_until,omitzero"`
+}
+
+// Tracker учитывает действия пользователей и проверяет лимиты. Счётчики обработок, загрузок,
+// нарушений и блокировки хранятся в JSON файле и переживают перезапуск и истечение сессий;
+// ограничитель частоты команд живёт только в памяти.
+type Tracker struct {
+	mu       sync.Mutex
+	limits   Limits
+	path     string
+	users    map[int64]*userState
+	limiters map[int64]*rate.Limiter
+	rejected map[int64]bool
+	now      func() time.Time
+
+	lastSweep time.Time
+}
+
+// NewTracker создаёт Tracker и загружает сохранённые счётчики из файла path
+func NewTracker(path string, limits Limits) (*Tracker, error) {
+	if limits.CommandsPerMinute > 0 && limits.CommandBurst <= 0 {
+		limits.CommandBurst = DefaultCommandBurst
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create quota directory: %w", err)
+	}
+
+	t := &Tracker{
+		limits:    limits,
+		path:      path,
+		users:     make(map[int64]*userState),
+		limiters:  make(map[int64]*rate.Limiter),
+		rejected:  make(map[int64]bool),
+		now:       time.Now,
+		lastSweep: time.Now(),
+	}
+	if err := t.load(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Command учитывает команду или файл пользователя. Ошибка означает, что счётчики не удалось
+// сохранить на диск; решение при этом действительно.
+func (t *Tracker) Command(userID int64) (Decision, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if d, banned := t.checkBan(userID, now); banned {
+		return d, nil
+	}
+	if t.limits.CommandsPerMinute <= 0 {
+		return t.allow(userID), nil
+	}
+	t.sweepLimiters(now)
+
+	limiter, ok := t.limiters[userID]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(t.limits.CommandsPerMinute/60), t.limits.CommandBurst)
+		t.limiters[userID] = limiter
+	}
+	r := limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return t.violate(userID, now, ViolationCommandRate, delay)
+	}
+	return t.allow(userID), nil
+}
+
+// Process учитывает запуск обработки
+func (t *Tracker) Process(userID int64) (Decision, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if d, banned := t.checkBan(userID, now); banned {
+		return d, nil
+	}
+
+	state := t.state(userID, now)
+	if retry := windowRetry(state.Processes, t.limits.ProcessPerHour, time.Hour, now); retry > 0 {
+		return t.violate(userID, now, ViolationProcessQuota, retry)
+	}
+	if retry := windowRetry(state.Processes, t.limits.ProcessPerDay, day, now); retry > 0 {
+		return t.violate(userID, now, ViolationProcessQuota, retry)
+	}
+
+	state.Processes = append(state.Processes, now)
+	return t.allow(userID), t.save()
+}
+
+// Upload учитывает загрузку файла размером size байт
+func (t *Tracker) Upload(userID, size int64) (Decision, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if d, banned := t.checkBan(userID, now); banned {
+		return d, nil
+	}
+
+	state := t.state(userID, now)
+	if limit := t.limits.UploadBytesPerDay; limit > 0 {
+		var used int64
+		for _, u := range state.Uploads {
+			used += u.Bytes
+		}
+		if used+size > limit {
+			// Ждём, пока из окна выйдет достаточно старых загрузок
+			retry := day
+			for _, u := range state.Uploads {
+				used -= u.Bytes
+				if used+size <= limit {
+					retry = u.At.Add(day).Sub(now)
+					break
+				}
+			}
+			return t.violate(userID, now, ViolationUploadQuota, retry)
+		}
+	}
+
+	state.Uploads = append(state.Uploads, upload{At: now, Bytes: size})
+	return t.allow(userID), t.save()
+}
+
+// Banned возвращает время окончания блокировки пользователя
+func (t *Tracker) Banned(userID int64) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.users[userID]
+	if !ok || !state.BannedUntil.After(t.now()) {
+		return time.Time{}, false
+	}
+	return state.BannedUntil, true
+}
+
+// Bans возвращает действующие блокировки, начиная с самой долгой
+func (t *Tracker) Bans() []Ban {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	var bans []Ban
+	for userID, state := range t.users {
+		if state.BannedUntil.After(now) {
+			bans = append(bans, Ban{UserID: userID, Until: state.BannedUntil})
+		}
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Until.After(bans[j].Until)
+	})
+	return bans
+}
+
+// Unban снимает блокировку и сбрасывает нарушения; возвращает false, если блокировки не было
+func (t *Tracker) Unban(userID int64) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.users[userID]
+	if !ok || !state.BannedUntil.After(t.now()) {
+		return false, nil
+	}
+	state.BannedUntil = time.Time{}
+	state.Violations = nil
+	delete(t.rejected, userID)
+	return true, t.save()
+}
+
+// checkBan отклоняет действие заблокированного пользователя
+func (t *Tracker) checkBan(userID int64, now time.Time) (Decision, bool) {
+	state, ok := t.users[userID]
+	if !ok || !state.BannedUntil.After(now) {
+		return Decision{}, false
+	}
+	return t.reject(userID, ViolationBanned, state.BannedUntil.Sub(now)), true
+}
+
+// violate отклоняет действие, учитывает нарушение и блокирует пользователя, если нарушений
+// за BanWindow набралось BanAfter
+func (t *Tracker) violate(userID int64, now time.Time, violation string, retry time.Duration) (Decision, error) {
+	d := t.reject(userID, violation, retry)
+	if t.limits.BanAfter <= 0 || t.limits.BanDuration <= 0 {
+		return d, nil
+	}
+
+	state := t.state(userID, now)
+	state.Violations = append(state.Violations, now)
+	if len(state.Violations) >= t.limits.BanAfter {
+		state.BannedUntil = now.Add(t.limits.BanDuration)
+		state.Violations = nil
+		d.Banned = true
+		d.Notify = true
+		d.RetryAfter = t.limits.BanDuration
+	}
+	return d, t.save()
+}
+
+// reject формирует отказ; Notify выставляется только для первого отказа подряд
+func (t *Tracker) reject(userID int64, violation string, retry time.Duration) Decision {
+	notify := !t.rejected[userID]
+	t.rejected[userID] = true
+	return Decision{Violation: violation, RetryAfter: retry, Notify: notify}
+}
+
+// allow разрешает действие и сбрасывает признак отказа
+func (t *Tracker) allow(userID int64) Decision {
+	delete(t.rejected, userID)
+	return Decision{Allowed: true}
+}
+
+// state возвращает счётчики пользователя, удаляя записи за пределами окон
+func (t *Tracker) state(userID int64, now time.Time) *userState {
+	state, ok := t.users[userID]
+	if !ok {
+		state = &userState{}
+		t.users[userID] = state
+	}
+	t.prune(state, now)
+	return state
+}
+
+// prune удаляет записи старше суток и нарушения вне окна BanWindow
+func (t *Tracker) prune(state *userState, now time.Time) {
+	state.Processes = dropBefore(state.Processes, now.Add(-day))
+
+	kept := state.Uploads[:0]
+	for _, u := range state.Uploads {
+		if u.At.After(now.Add(-day)) {
+			kept = append(kept, u)
+		}
+	}
+	state.Uploads = kept
+
+	state.Violations = dropBefore(state.Violations, now.Add(-t.limits.BanWindow))
+}
+
+// empty сообщает, что о пользователе нечего хранить
+func (s *userState) empty(now time.Time) bool {
+	return len(s.Processes) == 0 && len(s.Uploads) == 0 && len(s.Violations) == 0 && !s.BannedUntil.After(now)
+}
+
+// load читает сохранённые счётчики; отсутствие файла означает пустое состояние
+func (t *Tracker) load() error {
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read quotas: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &t.users); err != nil {
+		return fmt.Errorf("failed to unmarshal quotas: %w", err)
+	}
+	return nil
+}
+
+// save атомарно записывает счётчики, пропуская пользователей без активных записей.
+// При ошибке счётчики остаются в памяти и записываются при следующем изменении.
+func (t *Tracker) save() error {
+	now := t.now()
+	stored := make(map[int64]*userState, len(t.users))
+	for userID, state := range t.users {
+		t.prune(state, now)
+		if state.empty(now) {
+			delete(t.users, userID)
+			continue
+		}
+		stored[userID] = state
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quotas: %w", err)
+	}
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write quotas: %w", err)
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		return fmt.Errorf("failed to replace quotas: %w", err)
+	}
+	return nil
+}
+
+// sweepLimiters удаляет ограничители пользователей, которые успели накопить полный запас команд
+func (t *Tracker) sweepLimiters(now time.Time) {
+	if now.Sub(t.lastSweep) < limiterSweepInterval {
+		return
+	}
+	t.lastSweep = now
+	for userID, limiter := range t.limiters {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(t.limiters, userID)
+		}
+	}
+}
+
+// windowRetry возвращает, через сколько освободится место в окне window при лимите limit;
+// ноль — место есть или лимит отключён
+func windowRetry(times []time.Time, limit int, window time.Duration, now time.Time) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	var inWindow []time.Time
+	for _, at := range times {
+		if at.After(now.Add(-window)) {
+			inWindow = append(inWindow, at)
+		}
+	}
+	if len(inWindow) < limit {
+		return 0
+	}
+	return inWindow[len(inWindow)-limit].Add(window).Sub(now)
+}
+
+// dropBefore удаляет отметки времени не позже cutoff
+func dropBefore(times []time.Time, cutoff time.Time) []time.Time {
+	kept := times[:0]
+	for _, at := range times {
+		if at.After(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+	return kept
+}
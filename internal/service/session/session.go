@@ -1,8 +1,14 @@
 package session
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/filter"
 )
 
 // State представляет состояние сессии
@@ -15,13 +21,44 @@ const (
 	StateComplete   State = "complete"
 )
 
+// Settings пользовательские настройки анализа, действующие до истечения сессии
+type Settings struct {
+	ExcludeBots bool
+	Filter      filter.Spec
+
+	// IncludeChannels считает участниками каналы и анонимных админов
+	IncludeChannels bool
+
+	// ExtractContacts явное согласие пользователя на извлечение телефонов и email
+	ExtractContacts bool
+
+	// HideCharts отключает отправку графиков после обработки
+	HideCharts bool
+
+	// Language язык, выбранный командой /lang; пустая строка — язык из профиля Telegram
+	Language string
+	// ClientLanguage код языка из профиля Telegram (From.LanguageCode)
+	ClientLanguage string
+
+	// Переопределения настроек экстрактора; nil — значение из конфигурации бота
+	MinMessages     *int
+	IncludeMentions *bool
+	CaseSensitive   *bool
+}
+
 // Session хранит информацию о сессии пользователя
 type Session struct {
+	// ID случайный идентификатор сессии для корреляции логов, не связанный с пользователем
+	ID        string
 	UserID    int64
 	State     State
 	Files     []string
+	Settings  Settings
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// TotalSize суммарный размер файлов сессии в байтах
+	TotalSize int64
 }
 
 // Manager управляет сессиями пользователей in-memory
@@ -29,6 +66,29 @@ type Manager struct {
 	mu       sync.RWMutex
 	sessions map[int64]*Session
 	timeout  time.Duration
+	onExpire func(Session)
+}
+
+// newSession создаёт пустую сессию пользователя
+func newSession(userID int64) *Session {
+	now := time.Now()
+	return &Session{
+		ID:        newSessionID(),
+		UserID:    userID,
+		State:     StateEmpty,
+		Files:     make([]string, 0),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// newSessionID генерирует случайный идентификатор сессии
+func newSessionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
 }
 
 // NewManager создаёт новый Manager с timeout для очистки сессий
@@ -54,13 +114,7 @@ func (sm *Manager) GetOrCreate(userID int64) *Session {
 		return session
 	}
 
-	session := &Session{
-		UserID:    userID,
-		State:     StateEmpty,
-		Files:     make([]string, 0),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
+	session := newSession(userID)
 
 	sm.sessions[userID] = session
 	return session
@@ -74,24 +128,19 @@ func (sm *Manager) Get(userID int64) *Session {
 	return sm.sessions[userID]
 }
 
-// AddFile добавляет файл в сессию и обновляет состояние
-func (sm *Manager) AddFile(userID int64, filePath string) (*Session, error) {
+// AddFile добавляет файл размером size байт в сессию и обновляет состояние
+func (sm *Manager) AddFile(userID int64, filePath string, size int64) (*Session, error) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
 	session, exists := sm.sessions[userID]
 	if !exists {
-		session = &Session{
-			UserID:    userID,
-			State:     StateEmpty,
-			Files:     make([]string, 0),
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
+		session = newSession(userID)
 		sm.sessions[userID] = session
 	}
 
 	session.Files = append(session.Files, filePath)
+	session.TotalSize += size
 	session.State = StateLoading
 	session.UpdatedAt = time.Now()
 
@@ -125,6 +174,42 @@ func (sm *Manager) SetState(userID int64, state State) {
 	}
 }
 
+// GetSettings возвращает настройки пользователя или настройки по умолчанию
+func (sm *Manager) GetSettings(userID int64) Settings {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	if session, exists := sm.sessions[userID]; exists {
+		return session.Settings
+	}
+	return Settings{}
+}
+
+// UpdateSettings изменяет настройки пользователя, создавая сессию при необходимости
+func (sm *Manager) UpdateSettings(userID int64, update func(*Settings)) Settings {
+	session := sm.GetOrCreate(userID)
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	update(&session.Settings)
+	session.UpdatedAt = time.Now()
+	return session.Settings
+}
+
+// ClearFiles убирает файлы из сессии, сохраняя настройки пользователя
+func (sm *Manager) ClearFiles(userID int64) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if session, exists := sm.sessions[userID]; exists {
+		session.Files = make([]string, 0)
+		session.TotalSize = 0
+		session.State = StateEmpty
+		session.UpdatedAt = time.Now()
+	}
+}
+
 // Clear очищает сессию пользователя
 func (sm *Manager) Clear(userID int64) {
 	sm.mu.Lock()
@@ -146,6 +231,39 @@ func (sm *Manager) FileCount(userID int64) int {
 	return len(session.Files)
 }
 
+// List возвращает копии всех сессий, начиная с последней активной
+func (sm *Manager) List() []Session {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	result := make([]Session, 0, len(sm.sessions))
+	for _, session := range sm.sessions {
+		s := *session
+		s.Files = append([]string(nil), session.Files...)
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UpdatedAt.After(result[j].UpdatedAt)
+	})
+	return result
+}
+
+// OnExpire задаёт функцию, вызываемую для каждой истёкшей сессии после её удаления
+func (sm *Manager) OnExpire(fn func(Session)) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.onExpire = fn
+}
+
+// Count возвращает количество активных сессий
+func (sm *Manager) Count() int {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return len(sm.sessions)
+}
+
 // cleanupExpired запускается в отдельной горутине и очищает старые сессии
 func (sm *Manager) cleanupExpired() {
 	ticker := time.NewTicker(5 * time.Minute)
@@ -154,13 +272,23 @@ func (sm *Manager) cleanupExpired() {
 	for range ticker.C {
 		sm.mu.Lock()
 
+		var expired []Session
 		now := time.Now()
 		for userID, session := range sm.sessions {
 			if now.Sub(session.UpdatedAt) > sm.timeout {
+				expired = append(expired, *session)
 				delete(sm.sessions, userID)
 			}
 		}
+		onExpire := sm.onExpire
 
 		sm.mu.Unlock()
+
+		// Обработчик вызывается вне блокировки: он может обращаться к Manager
+		if onExpire != nil {
+			for _, session := range expired {
+				onExpire(session)
+			}
+		}
 	}
 }
@@ -0,0 +1,274 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Nikalively/telegram-export-parser/parser"
+
+	"github.com/MaxFando/tg-export-chat-analyzer/internal/service/metadata"
+)
+
+// TopAuthorsLimit количество авторов в рейтинге самых активных участников
+const TopAuthorsLimit = 10
+
+// Labels подписи графиков и таблиц отчёта активности на языке пользователя
+type Labels struct {
+	// Weekdays дни недели в порядке строк тепловой карты (с понедельника)
+	Weekdays [7]string
+
+	// Заголовки колонок и легенды
+	Date        string
+	WeekStart   string
+	DayHour     string
+	Messages    string
+	ActiveUsers string
+
+	// Заголовки графиков
+	DailyTitle      string
+	WeeklyTitle     string
+	HoursTitle      string
+	TopAuthorsTitle string
+}
+
+// DefaultLabels подписи на русском языке
+var DefaultLabels = Labels{
+	Weekdays:        [7]string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"},
+	Date:            "Дата",
+	WeekStart:       "Неделя с",
+	DayHour:         "День / час",
+	Messages:        "Сообщений",
+	ActiveUsers:     "Активных участников",
+	DailyTitle:      "Сообщения по дням",
+	WeeklyTitle:     "Сообщения по неделям",
+	HoursTitle:      "Активность по часам",
+	TopAuthorsTitle: "Самые активные участники",
+}
+
+// OrDefault возвращает подписи по умолчанию, если подписи не заданы
+func (l Labels) OrDefault() Labels {
+	if l == (Labels{}) {
+		return DefaultLabels
+	}
+	return l
+}
+
+// DayCount активность за день
+type DayCount struct {
+	Date        time.Time
+	Messages    int
+	ActiveUsers int
+}
+
+// WeekCount активность за неделю, начиная с понедельника
+type WeekCount struct {
+	WeekStart   time.Time
+	Messages    int
+	ActiveUsers int
+}
+
+// AuthorCount количество сообщений автора
+type AuthorCount struct {
+	UserID   string
+	Name     string
+	Messages int
+}
+
+// Label возвращает имя автора из экспорта или его ID, если имя неизвестно
+func (a AuthorCount) Label() string {
+	if strings.TrimSpace(a.Name) != "" {
+		return a.Name
+	}
+	return a.UserID
+}
+
+// Report агрегаты активности чата по времени
+type Report struct {
+	Messages    int
+	ActiveUsers int
+	From        time.Time
+	To          time.Time
+
+	Daily  []DayCount
+	Weekly []WeekCount
+
+	// Heatmap количество сообщений по дню недели (0 — понедельник) и часу
+	Heatmap [7][24]int
+
+	// TopAuthors самые активные авторы по убыванию числа сообщений
+	TopAuthors []AuthorCount
+}
+
+// Empty сообщает, что в отчёте нет данных
+func (r Report) Empty() bool {
+	return r.Messages == 0
+}
+
+// BusiestDay возвращает самый активный день
+func (r Report) BusiestDay() DayCount {
+	var best DayCount
+	for _, d := range r.Daily {
+		if d.Messages > best.Messages {
+			best = d
+		}
+	}
+	return best
+}
+
+// PeakHour возвращает день недели и час с наибольшим числом сообщений
+func (r Report) PeakHour() (weekday, hour, messages int) {
+	for d := range r.Heatmap {
+		for h := range r.Heatmap[d] {
+			if r.Heatmap[d][h] > messages {
+				weekday, hour, messages = d, h, r.Heatmap[d][h]
+			}
+		}
+	}
+	return weekday, hour, messages
+}
+
+// AveragePerDay возвращает среднее число сообщений за день периода, включая дни без сообщений
+func (r Report) AveragePerDay() float64 {
+	if r.Empty() {
+		return 0
+	}
+	days := int(dayStart(r.To).Sub(dayStart(r.From)).Hours()/24) + 1
+	return float64(r.Messages) / float64(days)
+}
+
+// Hourly возвращает распределение сообщений по часам суток за все дни недели
+func (r Report) Hourly() [24]int {
+	var hours [24]int
+	for d := range r.Heatmap {
+		for h, n := range r.Heatmap[d] {
+			hours[h] += n
+		}
+	}
+	return hours
+}
+
+// Compute строит агрегаты по событиям; события без даты и служебные сообщения не учитываются.
+// Имена авторов берутся из метаданных экспорта, если они доступны.
+func Compute(events []parser.Event, index metadata.Index) Report {
+	var r Report
+
+	daily := make(map[time.Time]int)
+	dailyUsers := make(map[time.Time]map[string]bool)
+	weekly := make(map[time.Time]int)
+	weeklyUsers := make(map[time.Time]map[string]bool)
+	users := make(map[string]bool)
+	authors := make(map[string]*AuthorCount)
+
+	for _, event := range events {
+		if event.Date.IsZero() {
+			continue
+		}
+		if msg, ok := index.Lookup(event); ok && msg.Type == metadata.TypeService {
+			continue
+		}
+
+		r.Messages++
+		if r.From.IsZero() || event.Date.Before(r.From) {
+			r.From = event.Date
+		}
+		if event.Date.After(r.To) {
+			r.To = event.Date
+		}
+
+		day := dayStart(event.Date)
+		week := weekStart(event.Date)
+		daily[day]++
+		weekly[week]++
+
+		if event.FromID != "" {
+			users[event.FromID] = true
+			countAuthor(authors, event, index)
+			addUser(dailyUsers, day, event.FromID)
+			addUser(weeklyUsers, week, event.FromID)
+		}
+
+		r.Heatmap[weekdayIndex(event.Date)][event.Date.Hour()]++
+	}
+
+	r.ActiveUsers = len(users)
+
+	// Заполняем дни без сообщений, чтобы графики отражали реальную шкалу времени
+	if !r.Empty() {
+		for day := dayStart(r.From); !day.After(dayStart(r.To)); day = day.AddDate(0, 0, 1) {
+			r.Daily = append(r.Daily, DayCount{
+				Date:        day,
+				Messages:    daily[day],
+				ActiveUsers: len(dailyUsers[day]),
+			})
+		}
+	}
+
+	for week, count := range weekly {
+		r.Weekly = append(r.Weekly, WeekCount{
+			WeekStart:   week,
+			Messages:    count,
+			ActiveUsers: len(weeklyUsers[week]),
+		})
+	}
+	sort.Slice(r.Weekly, func(i, j int) bool {
+		return r.Weekly[i].WeekStart.Before(r.Weekly[j].WeekStart)
+	})
+
+	for _, a := range authors {
+		r.TopAuthors = append(r.TopAuthors, *a)
+	}
+	sort.Slice(r.TopAuthors, func(i, j int) bool {
+		if r.TopAuthors[i].Messages != r.TopAuthors[j].Messages {
+			return r.TopAuthors[i].Messages > r.TopAuthors[j].Messages
+		}
+		return r.TopAuthors[i].UserID < r.TopAuthors[j].UserID
+	})
+	if len(r.TopAuthors) > TopAuthorsLimit {
+		r.TopAuthors = r.TopAuthors[:TopAuthorsLimit]
+	}
+
+	return r
+}
+
+// countAuthor учитывает сообщение автора, запоминая его имя из метаданных
+func countAuthor(authors map[string]*AuthorCount, event parser.Event, index metadata.Index) {
+	a, ok := authors[event.FromID]
+	if !ok {
+		a = &AuthorCount{UserID: event.FromID}
+		authors[event.FromID] = a
+	}
+	a.Messages++
+
+	if a.Name == "" {
+		if msg, ok := index.Lookup(event); ok {
+			a.Name = msg.From
+			if a.Name == "" {
+				a.Name = msg.Actor
+			}
+		}
+	}
+}
+
+// addUser добавляет автора в множество активных пользователей периода
+func addUser(sets map[time.Time]map[string]bool, period time.Time, userID string) {
+	if sets[period] == nil {
+		sets[period] = make(map[string]bool)
+	}
+	sets[period][userID] = true
+}
+
+// dayStart возвращает начало дня
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// weekStart возвращает понедельник недели, в которую попадает t
+func weekStart(t time.Time) time.Time {
+	return dayStart(t).AddDate(0, 0, -weekdayIndex(t))
+}
+
+// weekdayIndex возвращает номер дня недели, начиная с понедельника
+func weekdayIndex(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
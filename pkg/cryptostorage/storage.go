@@ -0,0 +1,178 @@
+package cryptostorage
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/inqast/fstorage/storage"
+)
+
+// keySize размер ключа сессии
+const keySize = 32
+
+// Storage шифрует временные файлы AES-GCM ключом сессии. Ключи создаются случайно и хранятся
+// только в памяти процесса: после перезапуска или Forget файлы на диске нечитаемы.
+type Storage struct {
+	base         storage.TempStorage
+	secureDelete bool
+
+	mu    sync.Mutex
+	keys  map[string][]byte
+	saved map[string]bool
+}
+
+// Option настраивает Storage
+type Option func(*Storage)
+
+// WithSecureDelete перед удалением перезаписывает файл нулями. На SSD и файловых системах
+// с копированием при записи перезапись не гарантирует уничтожение данных, основную защиту
+// даёт шифрование.
+func WithSecureDelete(enabled bool) Option {
+	return func(s *Storage) {
+		s.secureDelete = enabled
+	}
+}
+
+// New оборачивает хранилище base
+func New(base storage.TempStorage, opts ...Option) *Storage {
+	s := &Storage{
+		base:  base,
+		keys:  make(map[string][]byte),
+		saved: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Session возвращает хранилище сессии sessionID. Ключ сессии создаётся при первом обращении;
+// файлы, сохранённые в одной сессии, нельзя прочитать через другую.
+func (s *Storage) Session(sessionID string) storage.TempStorage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[sessionID]
+	if !ok {
+		key = make([]byte, keySize)
+		if _, err := rand.Read(key); err != nil {
+			// crypto/rand не возвращает ошибок на поддерживаемых платформах
+			panic(fmt.Sprintf("failed to generate session key: %v", err))
+		}
+		s.keys[sessionID] = key
+	}
+	return &sessionStorage{parent: s, key: key}
+}
+
+// Forget удаляет ключ сессии: оставшиеся на диске файлы сессии становятся нечитаемыми
+func (s *Storage) Forget(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Ключ не затирается: его ещё может использовать загрузка, начатая до Forget
+	delete(s.keys, sessionID)
+}
+
+// delete удаляет файл, при необходимости перезаписывая его содержимое
+func (s *Storage) delete(path string) error {
+	s.mu.Lock()
+	known := s.saved[path]
+	delete(s.saved, path)
+	s.mu.Unlock()
+
+	// Перезаписываются только файлы, сохранённые этим хранилищем: путь уже проверен base
+	if s.secureDelete && known {
+		if err := overwrite(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return s.base.Delete(path)
+}
+
+// overwrite заполняет файл нулями и сбрасывает его на диск
+func overwrite(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err == nil {
+		_, err = io.CopyN(f, zeroReader{}, info.Size())
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to overwrite file: %w", err)
+	}
+	return nil
+}
+
+// zeroReader бесконечный поток нулей
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// sessionStorage хранилище одной сессии
+type sessionStorage struct {
+	parent *Storage
+	key    []byte
+}
+
+// Save шифрует содержимое r по мере записи на диск
+func (s *sessionStorage) Save(name string, r io.Reader) (string, error) {
+	enc, err := newEncryptReader(r, s.key)
+	if err != nil {
+		return "", err
+	}
+	path, err := s.parent.base.Save(name, enc)
+	if err != nil {
+		return "", err
+	}
+
+	s.parent.mu.Lock()
+	s.parent.saved[path] = true
+	s.parent.mu.Unlock()
+	return path, nil
+}
+
+// Read открывает файл и расшифровывает его при чтении
+func (s *sessionStorage) Read(path string) (io.ReadCloser, error) {
+	f, err := s.parent.base.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	dec, err := newDecryptReader(f, s.key)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return dec, nil
+}
+
+// Delete удаляет файл
+func (s *sessionStorage) Delete(path string) error {
+	return s.parent.delete(path)
+}
+
+// DeleteAll удаляет файлы и возвращает все ошибки удаления
+func (s *sessionStorage) DeleteAll(paths []string) error {
+	var errs []error
+	for _, path := range paths {
+		if err := s.parent.delete(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,202 @@
+package cryptostorage
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Формат зашифрованного файла:
+//
+//	magic (4 байта) | salt (16 байт) | блок 1 | блок 2 | ... | последний блок
+//
+// Ключ файла — HMAC-SHA256(ключ сессии, salt), поэтому у каждого файла свой ключ AES-256.
+// Каждый блок — AES-GCM от chunkSize байт открытого текста (последний может быть короче
+// или пустым). Nonce блока — его номер и признак последнего блока: переставить, удалить
+// или обрезать блоки незаметно нельзя.
+const (
+	magic     = "TGE1"
+	saltSize  = 16
+	chunkSize = 64 << 10
+	tagSize   = 16
+	nonceSize = 12
+)
+
+// ErrCorrupted файл повреждён, обрезан или зашифрован другим ключом
+var ErrCorrupted = errors.New("encrypted file is corrupted or the key does not match")
+
+// fileCipher создаёт AES-GCM с ключом файла, выведенным из ключа сессии и salt
+func fileCipher(sessionKey, salt []byte) (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, sessionKey)
+	mac.Write(salt)
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce возвращает nonce блока с номером index
+func chunkNonce(index uint64, last bool) []byte {
+	nonce := make([]byte, nonceSize)
+	binary.BigEndian.PutUint64(nonce[3:11], index)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// encryptReader шифрует поток по блокам по мере чтения, не держа файл в памяти целиком
+type encryptReader struct {
+	src    io.Reader
+	aead   cipher.AEAD
+	index  uint64
+	buf    []byte // зашифрованные данные, ещё не отданные читателю
+	next   []byte // прочитанный вперёд блок открытого текста
+	nextN  int
+	srcErr error
+	done   bool
+}
+
+func newEncryptReader(src io.Reader, sessionKey []byte) (*encryptReader, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	aead, err := fileCipher(sessionKey, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &encryptReader{
+		src:  src,
+		aead: aead,
+		buf:  append([]byte(magic), salt...),
+		next: make([]byte, chunkSize),
+	}
+	// Читаем первый блок заранее: признак последнего блока известен только после чтения следующего
+	r.nextN, r.srcErr = io.ReadFull(src, r.next)
+	return r, nil
+}
+
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.seal(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// seal шифрует очередной блок в buf
+func (r *encryptReader) seal() error {
+	chunk, err := r.nextN, r.srcErr
+	plain := append([]byte(nil), r.next[:chunk]...)
+
+	var last bool
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	default:
+		r.nextN, r.srcErr = io.ReadFull(r.src, r.next)
+		if errors.Is(r.srcErr, io.EOF) {
+			last = true
+		}
+	}
+
+	r.buf = r.aead.Seal(r.buf[:0], chunkNonce(r.index, last), plain, nil)
+	r.index++
+	r.done = last
+	return nil
+}
+
+// decryptReader расшифровывает поток блоками и проверяет целостность каждого блока
+type decryptReader struct {
+	src   *bufio.Reader
+	close io.Closer
+	aead  cipher.AEAD
+	index uint64
+	chunk []byte
+	buf   []byte
+	done  bool
+}
+
+func newDecryptReader(src io.ReadCloser, sessionKey []byte) (*decryptReader, error) {
+	br := bufio.NewReaderSize(src, chunkSize+tagSize)
+
+	header := make([]byte, len(magic)+saltSize)
+	if _, err := io.ReadFull(br, header); err != nil || string(header[:len(magic)]) != magic {
+		return nil, ErrCorrupted
+	}
+	aead, err := fileCipher(sessionKey, header[len(magic):])
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		src:   br,
+		close: src,
+		aead:  aead,
+		chunk: make([]byte, chunkSize+tagSize),
+	}, nil
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// open расшифровывает очередной блок в buf
+func (r *decryptReader) open() error {
+	n, err := io.ReadFull(r.src, r.chunk)
+	if errors.Is(err, io.EOF) {
+		// Поток закончился без последнего блока: файл обрезан
+		return ErrCorrupted
+	}
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+
+	// Блок последний, если за ним ничего нет; неполный блок может быть только последним
+	_, peekErr := r.src.Peek(1)
+	last := errors.Is(peekErr, io.EOF)
+	if peekErr != nil && !last {
+		return peekErr
+	}
+
+	plain, err := r.aead.Open(r.chunk[:0], chunkNonce(r.index, last), r.chunk[:n], nil)
+	if err != nil {
+		return ErrCorrupted
+	}
+	r.buf = plain
+	r.index++
+	r.done = last
+	return nil
+}
+
+func (r *decryptReader) Close() error {
+	return r.close.Close()
+}
@@ -1,15 +1,21 @@
 package logger
 
 import (
-	"crypto/sha256"
+	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
 	"strings"
+	"time"
 )
 
-// Logger предоставляет логирование без PII (личных данных)
+// Logger предоставляет структурированное логирование без PII (личных данных) на основе log/slog.
+// Значения чувствительных полей и найденные в строках персональные данные заменяются HMAC.
 type Logger struct {
-	level Level
+	slog  *slog.Logger
+	level *slog.LevelVar
 }
 
 type Level string
@@ -21,90 +27,179 @@ const (
 	LevelError Level = "error"
 )
 
-func New(level string) *Logger {
-	lvl := Level(strings.ToLower(level))
-	switch lvl {
-	case LevelDebug, LevelInfo, LevelWarn, LevelError:
-		return &Logger{level: lvl}
+// Format формат записей лога
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// Option настраивает Logger
+type Option func(*options)
+
+type options struct {
+	format    Format
+	output    io.Writer
+	addSource bool
+	hashKey   []byte
+}
+
+// WithFormat задаёт формат записей: text или json; неизвестные значения заменяются на text
+func WithFormat(format string) Option {
+	return func(o *options) {
+		if Format(strings.ToLower(format)) == FormatJSON {
+			o.format = FormatJSON
+		} else {
+			o.format = FormatText
+		}
+	}
+}
+
+// WithOutput задаёт приёмник записей; по умолчанию stderr
+func WithOutput(w io.Writer) Option {
+	return func(o *options) {
+		if w != nil {
+			o.output = w
+		}
+	}
+}
+
+// WithHashKey задаёт ключ HMAC для псевдонимизации персональных данных.
+// С постоянным ключом хэши совпадают между перезапусками; без ключа он генерируется при старте.
+func WithHashKey(key []byte) Option {
+	return func(o *options) {
+		if len(key) > 0 {
+			o.hashKey = key
+		}
+	}
+}
+
+// WithSource добавляет в записи файл и строку вызова
+func WithSource(enabled bool) Option {
+	return func(o *options) {
+		o.addSource = enabled
+	}
+}
+
+func New(level string, opts ...Option) *Logger {
+	o := options{
+		format: FormatText,
+		output: os.Stderr,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	lvl := new(slog.LevelVar)
+	lvl.Set(parseLevel(level))
+
+	handlerOpts := &slog.HandlerOptions{
+		AddSource:   o.addSource,
+		Level:       lvl,
+		ReplaceAttr: newRedactor(o.hashKey).replaceAttr,
+	}
+
+	var handler slog.Handler
+	switch o.format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(o.output, handlerOpts)
 	default:
-		return &Logger{level: LevelInfo}
+		handler = slog.NewTextHandler(o.output, handlerOpts)
 	}
+
+	return &Logger{slog: slog.New(handler), level: lvl}
 }
 
-func (l *Logger) Debug(msg string, keysAndValues ...interface{}) {
-	if l.shouldLog(LevelDebug) {
-		l.log("DEBUG", msg, keysAndValues...)
+// OpenOutput открывает приёмник лога: stdout, stderr (по умолчанию) или файл для дозаписи
+func OpenOutput(name string) (io.Writer, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "stderr":
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	}
+
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log output: %w", err)
 	}
+	return f, nil
+}
+
+// With возвращает логгер, добавляющий поля ко всем записям (например, update_id и session_id запроса)
+func (l *Logger) With(keysAndValues ...interface{}) *Logger {
+	return &Logger{slog: l.slog.With(keysAndValues...), level: l.level}
+}
+
+func (l *Logger) Debug(msg string, keysAndValues ...interface{}) {
+	l.log(slog.LevelDebug, msg, keysAndValues...)
 }
 
 func (l *Logger) Info(msg string, keysAndValues ...interface{}) {
-	if l.shouldLog(LevelInfo) {
-		l.log("INFO", msg, keysAndValues...)
-	}
+	l.log(slog.LevelInfo, msg, keysAndValues...)
 }
 
 func (l *Logger) Warn(msg string, keysAndValues ...interface{}) {
-	if l.shouldLog(LevelWarn) {
-		l.log("WARN", msg, keysAndValues...)
-	}
+	l.log(slog.LevelWarn, msg, keysAndValues...)
 }
 
 func (l *Logger) Error(msg string, keysAndValues ...interface{}) {
-	if l.shouldLog(LevelError) {
-		l.log("ERROR", msg, keysAndValues...)
-	}
+	l.log(slog.LevelError, msg, keysAndValues...)
 }
 
-func (l *Logger) shouldLog(checkLevel Level) bool {
-	levels := map[Level]int{
-		LevelDebug: 0,
-		LevelInfo:  1,
-		LevelWarn:  2,
-		LevelError: 3,
+// log создаёт запись с местом вызова из кода приложения, а не из пакета logger
+func (l *Logger) log(level slog.Level, msg string, keysAndValues ...interface{}) {
+	ctx := context.Background()
+	if !l.slog.Enabled(ctx, level) {
+		return
 	}
-	return levels[checkLevel] >= levels[l.level]
-}
-
-func (l *Logger) log(level, msg string, keysAndValues ...interface{}) {
-	var parts []string
-	parts = append(parts, fmt.Sprintf("[%s]", level))
-	parts = append(parts, msg)
-
-	for i := 0; i < len(keysAndValues); i += 2 {
-		if i+1 < len(keysAndValues) {
-			key := fmt.Sprintf("%v", keysAndValues[i])
-			val := fmt.Sprintf("%v", keysAndValues[i+1])
-			// Не логируем чувствительные данные
-			if isPrivateKey(key) {
-				val = hashValue(val)
-			}
-			parts = append(parts, fmt.Sprintf("%s=%v", key, val))
-		}
+
+	var pcs [1]uintptr
+	// Пропускаем runtime.Callers, log и публичный метод уровня
+	runtime.Callers(3, pcs[:])
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(keysAndValues...)
+	_ = l.slog.Handler().Handle(ctx, r)
+}
+
+// SetLevel меняет уровень логирования во время работы. Уровень общий для логгера
+// и всех логгеров, полученных через With.
+func (l *Logger) SetLevel(level string) error {
+	switch Level(strings.ToLower(strings.TrimSpace(level))) {
+	case LevelDebug, LevelInfo, LevelWarn, LevelError:
+	default:
+		return fmt.Errorf("unknown log level %q", level)
 	}
+	l.level.Set(parseLevel(strings.TrimSpace(level)))
+	return nil
+}
 
-	log.Println(strings.Join(parts, " "))
-}
-
-func isPrivateKey(key string) bool {
-	private := map[string]bool{
-		"username":  true,
-		"userId":    true,
-		"user_id":   true,
-		"userID":    true,
-		"firstName": true,
-		"lastName":  true,
-		"bio":       true,
-		"file":      true,
-		"path":      true,
-		"token":     true,
-		"password":  true,
-		"email":     true,
-		"phone":     true,
+// Level возвращает текущий уровень логирования
+func (l *Logger) Level() Level {
+	switch l.level.Level() {
+	case slog.LevelDebug:
+		return LevelDebug
+	case slog.LevelWarn:
+		return LevelWarn
+	case slog.LevelError:
+		return LevelError
+	default:
+		return LevelInfo
 	}
-	return private[strings.ToLower(key)]
 }
 
-func hashValue(val string) string {
-	h := sha256.Sum256([]byte(val))
-	return fmt.Sprintf("%x", h[:8])
+// parseLevel переводит уровень из конфигурации в slog; неизвестные значения заменяются на info
+func parseLevel(level string) slog.Level {
+	switch Level(strings.ToLower(level)) {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
 }
@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// privateKeys нормализованные имена полей, значения которых всегда псевдонимизируются.
+// Имена сравниваются без учёта регистра, "_" и "-": userID, user_id и UserId совпадают.
+var privateKeys = map[string]bool{
+	"username":  true,
+	"userid":    true,
+	"firstname": true,
+	"lastname":  true,
+	"name":      true,
+	"bio":       true,
+	"text":      true,
+	"caption":   true,
+	"file":      true,
+	"filename":  true,
+	"path":      true,
+	"filepath":  true,
+	"token":     true,
+	"password":  true,
+	"secret":    true,
+	"email":     true,
+	"phone":     true,
+}
+
+// detector находит персональные данные в произвольной строке
+type detector struct {
+	kind string
+	re   *regexp.Regexp
+	// valid отсеивает ложные совпадения; nil — подходит любое совпадение
+	valid func(match string) bool
+}
+
+// detectors проверяются по порядку: email раньше username, токен раньше пути и телефона
+var detectors = []detector{
+	{kind: "token", re: regexp.MustCompile(`\d{6,12}:[A-Za-z0-9_-]{30,}`)},
+	{kind: "email", re: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	{kind: "username", re: regexp.MustCompile(`@[A-Za-z][A-Za-z0-9_]{3,31}`)},
+	{kind: "path", re: regexp.MustCompile(`(?:[A-Za-z]:)?(?:[/\\][^\s/\\:"'<>|]+){2,}`)},
+	{kind: "phone", re: regexp.MustCompile(`(?:\+|\b)\d[\d\s().-]{8,}\d\b`), valid: isPhone},
+}
+
+// redactor псевдонимизирует персональные данные в записях лога с помощью HMAC-SHA256
+type redactor struct {
+	key []byte
+}
+
+// newRedactor создаёт redactor; без ключа генерируется случайный ключ на время жизни процесса
+func newRedactor(key []byte) *redactor {
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic(fmt.Sprintf("failed to generate log hash key: %v", err))
+		}
+	}
+	return &redactor{key: key}
+}
+
+// replaceAttr реализует slog.HandlerOptions.ReplaceAttr, включая поля, добавленные через With
+func (r *redactor) replaceAttr(_ []string, a slog.Attr) slog.Attr {
+	switch a.Key {
+	case slog.TimeKey, slog.LevelKey, slog.MessageKey:
+		return a
+	case slog.SourceKey:
+		// Место вызова сворачиваем в строку "пакет/файл.go:строка": вложенное поле file
+		// не должно попасть под хэширование как путь к пользовательскому файлу
+		if src, ok := a.Value.Any().(*slog.Source); ok {
+			file := filepath.Join(filepath.Base(filepath.Dir(src.File)), filepath.Base(src.File))
+			return slog.String(slog.SourceKey, fmt.Sprintf("%s:%d", file, src.Line))
+		}
+		return a
+	}
+
+	value := a.Value.Resolve()
+
+	// Не логируем чувствительные данные
+	if isPrivateKey(a.Key) {
+		return slog.String(a.Key, r.hash(value.String()))
+	}
+
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.redact(value.String()))
+	case slog.KindAny:
+		// Ошибки часто содержат пути к файлам и ответы API с данными пользователя
+		switch v := value.Any().(type) {
+		case error:
+			return slog.String(a.Key, r.redact(v.Error()))
+		case fmt.Stringer:
+			return slog.String(a.Key, r.redact(v.String()))
+		case []byte:
+			return slog.String(a.Key, r.hash(string(v)))
+		}
+	}
+	return a
+}
+
+// redact заменяет найденные в строке персональные данные на "[вид:hmac]"
+func (r *redactor) redact(s string) string {
+	for _, d := range detectors {
+		s = d.re.ReplaceAllStringFunc(s, func(match string) string {
+			if d.valid != nil && !d.valid(match) {
+				return match
+			}
+			return "[" + d.kind + ":" + r.hash(match) + "]"
+		})
+	}
+	return s
+}
+
+// hash возвращает укороченный HMAC значения: одинаковые значения можно сопоставить, исходные — нельзя
+func (r *redactor) hash(val string) string {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(val))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// isPhone отличает номер телефона от дат и коротких чисел: в номере от 10 до 15 цифр
+func isPhone(match string) bool {
+	digits := 0
+	for _, c := range match {
+		if c >= '0' && c <= '9' {
+			digits++
+		}
+	}
+	return digits >= 10 && digits <= 15
+}
+
+// isPrivateKey сообщает, что значение поля нужно псевдонимизировать целиком
+func isPrivateKey(key string) bool {
+	return privateKeys[normalizeKey(key)]
+}
+
+// normalizeKey приводит имя поля к нижнему регистру без разделителей
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer("_", "", "-", "", ".", "").Replace(key)
+}
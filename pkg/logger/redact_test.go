@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+var testKey = []byte("test-log-hash-key")
+
+// Исходные значения, которые не должны попасть в лог ни в каком виде
+const (
+	testUserID   = 987654321
+	testToken    = "1234567890:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw8"
+	testEmail    = "ivan.petrov@example.com"
+	testUsername = "@ivan_petrov"
+	testPhone    = "+7 (912) 345-67-89"
+	testPath     = "/home/ivan/exports/result.json"
+	testName     = "Иван Петров"
+	testText     = "привет, это личное сообщение"
+)
+
+// rawValues значения, поиск которых в выводе означает утечку
+var rawValues = []string{
+	"987654321",
+	testToken,
+	testEmail,
+	"ivan.petrov",
+	testUsername,
+	"ivan_petrov",
+	testPhone,
+	"345-67-89",
+	testPath,
+	"/home/ivan",
+	testName,
+	testText,
+}
+
+// expectedHash вычисляет HMAC так же, как redactor, но независимо от него
+func expectedHash(key []byte, val string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(val))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+func TestNoPIIAtAnyLevel(t *testing.T) {
+	formats := []string{string(FormatJSON), string(FormatText)}
+	levels := []Level{LevelDebug, LevelInfo, LevelWarn, LevelError}
+
+	cases := []struct {
+		name string
+		log  func(l *Logger, level Level)
+	}{
+		{
+			name: "private keys",
+			log: func(l *Logger, level Level) {
+				write(l, level, "event",
+					"user_id", testUserID,
+					"userID", testUserID,
+					"UserId", testUserID,
+					"text", testText,
+					"first_name", testName)
+			},
+		},
+		{
+			name: "values in free text",
+			log: func(l *Logger, level Level) {
+				write(l, level, "event",
+					"detail", "token "+testToken+" from "+testEmail,
+					"contact", testUsername+" "+testPhone,
+					"location", "saved to "+testPath)
+			},
+		},
+		{
+			name: "error values",
+			log: func(l *Logger, level Level) {
+				write(l, level, "event",
+					"error", errors.New("open "+testPath+": bot"+testToken+" failed for "+testEmail))
+			},
+		},
+		{
+			name: "attrs from With",
+			log: func(l *Logger, level Level) {
+				write(l.With("user_id", testUserID, "first_name", testName, "detail", testUsername),
+					level, "event", "contact", testPhone)
+			},
+		},
+		{
+			name: "attrs in groups",
+			log: func(l *Logger, level Level) {
+				write(l.With(slog.Group("from", "userID", testUserID, "email", testEmail)),
+					level, "event",
+					slog.Group("message",
+						"text", testText,
+						"UserId", testUserID,
+						slog.Group("file", "detail", testPath),
+						"detail", testToken+" "+testPhone))
+			},
+		},
+	}
+
+	for _, format := range formats {
+		for _, level := range levels {
+			for _, tc := range cases {
+				t.Run(format+"/"+string(level)+"/"+tc.name, func(t *testing.T) {
+					var buf bytes.Buffer
+					l := New(string(LevelDebug), WithFormat(format), WithOutput(&buf), WithHashKey(testKey))
+
+					tc.log(l, level)
+
+					out := buf.String()
+					if out == "" {
+						t.Fatal("no log output")
+					}
+					for _, raw := range rawValues {
+						if strings.Contains(out, raw) {
+							t.Errorf("output contains %q:\n%s", raw, out)
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestHashStableForKey(t *testing.T) {
+	cases := []struct {
+		key   string
+		value any
+		want  string
+	}{
+		{key: "user_id", value: testUserID, want: expectedHash(testKey, "987654321")},
+		{key: "text", value: testText, want: expectedHash(testKey, testText)},
+		{key: "detail", value: testEmail, want: "[email:" + expectedHash(testKey, testEmail) + "]"},
+		{key: "detail", value: testUsername, want: "[username:" + expectedHash(testKey, testUsername) + "]"},
+		{key: "detail", value: testToken, want: "[token:" + expectedHash(testKey, testToken) + "]"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.key, func(t *testing.T) {
+			// Два независимых логгера с одним ключом дают одинаковые хэши
+			for range 2 {
+				var buf bytes.Buffer
+				l := New(string(LevelInfo), WithFormat(string(FormatJSON)), WithOutput(&buf), WithHashKey(testKey))
+				l.Info("event", tc.key, tc.value)
+
+				if !strings.Contains(buf.String(), `"`+tc.key+`":"`+tc.want+`"`) {
+					t.Errorf("want %s=%s, got:\n%s", tc.key, tc.want, buf.String())
+				}
+			}
+		})
+	}
+
+	var other bytes.Buffer
+	New(string(LevelInfo), WithFormat(string(FormatJSON)), WithOutput(&other), WithHashKey([]byte("other-key"))).
+		Info("event", "user_id", testUserID)
+	if strings.Contains(other.String(), expectedHash(testKey, "987654321")) {
+		t.Error("hash does not depend on the key")
+	}
+}
+
+// write пишет запись на заданном уровне
+func write(l *Logger, level Level, msg string, keysAndValues ...any) {
+	switch level {
+	case LevelDebug:
+		l.Debug(msg, keysAndValues...)
+	case LevelInfo:
+		l.Info(msg, keysAndValues...)
+	case LevelWarn:
+		l.Warn(msg, keysAndValues...)
+	case LevelError:
+		l.Error(msg, keysAndValues...)
+	}
+}
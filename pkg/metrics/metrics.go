@@ -0,0 +1,258 @@
+package metrics
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace префикс всех метрик бота
+const namespace = "tg_export_bot"
+
+// Причины отклонения загруженного файла
+const (
+	RejectFileLimit = "file_limit"
+	RejectFileSize  = "file_size"
+	RejectTotalSize = "total_size"
+	RejectDownload  = "download"
+	RejectStorage   = "storage"
+	RejectQuota     = "quota"
+)
+
+// Metrics собирает метрики бота для Prometheus.
+// Все методы безопасны для nil: без включённого /metrics бот работает с nil *Metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	updates            *prometheus.CounterVec
+	commands           *prometheus.CounterVec
+	filesUploaded      prometheus.Counter
+	filesRejected      *prometheus.CounterVec
+	bytesDownloaded    prometheus.Counter
+	parseFailures      *prometheus.CounterVec
+	processingDuration prometheus.Histogram
+	participants       prometheus.Histogram
+	apiErrors          *prometheus.CounterVec
+	accessDenied       *prometheus.CounterVec
+	quotaRejections    *prometheus.CounterVec
+	bans               prometheus.Counter
+}
+
+// New создаёт метрики в отдельном реестре вместе со стандартными метриками процесса и Go runtime
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		updates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "updates_total",
+			Help:      "Updates received from Telegram by type.",
+		}, []string{"type"}),
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commands_total",
+			Help:      "Bot commands received by command.",
+		}, []string{"command"}),
+		filesUploaded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "files_uploaded_total",
+			Help:      "Export files accepted into sessions.",
+		}),
+		filesRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "files_rejected_total",
+			Help:      "Export files rejected by reason.",
+		}, []string{"reason"}),
+		bytesDownloaded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "downloaded_bytes_total",
+			Help:      "Bytes downloaded from Telegram file storage.",
+		}),
+		parseFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "parse_failures_total",
+			Help:      "Export files that failed to parse by format.",
+		}, []string{"format"}),
+		processingDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "processing_duration_seconds",
+			Help:      "Duration of /process from parsing to sending the result.",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+		}),
+		participants: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "result_participants",
+			Help:      "Participants per analysis result.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+		}),
+		apiErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "telegram_api_errors_total",
+			Help:      "Failed Telegram Bot API requests by error code (network for transport errors).",
+		}, []string{"code"}),
+		accessDenied: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "access_denied_total",
+			Help:      "Messages rejected by the access policy by reason.",
+		}, []string{"reason"}),
+		quotaRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "quota_rejections_total",
+			Help:      "Actions rejected by per-user quotas and rate limits by violation.",
+		}, []string{"violation"}),
+		bans: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "user_bans_total",
+			Help:      "Temporary bans issued after repeated quota violations.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.updates,
+		m.commands,
+		m.filesUploaded,
+		m.filesRejected,
+		m.bytesDownloaded,
+		m.parseFailures,
+		m.processingDuration,
+		m.participants,
+		m.apiErrors,
+		m.accessDenied,
+		m.quotaRejections,
+		m.bans,
+	)
+	return m
+}
+
+// Handler возвращает HTTP обработчик /metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RegisterActiveSessions публикует количество активных сессий, запрашивая его при каждом сборе метрик
+func (m *Metrics) RegisterActiveSessions(count func() int) {
+	if m == nil {
+		return
+	}
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_sessions",
+		Help:      "Sessions currently held in memory.",
+	}, func() float64 {
+		return float64(count())
+	}))
+}
+
+// UpdateReceived учитывает обновление от Telegram
+func (m *Metrics) UpdateReceived(kind string) {
+	if m == nil {
+		return
+	}
+	m.updates.WithLabelValues(kind).Inc()
+}
+
+// CommandReceived учитывает команду; неизвестные команды учитываются как "unknown"
+func (m *Metrics) CommandReceived(command string) {
+	if m == nil {
+		return
+	}
+	m.commands.WithLabelValues(command).Inc()
+}
+
+// FileUploaded учитывает принятый файл
+func (m *Metrics) FileUploaded() {
+	if m == nil {
+		return
+	}
+	m.filesUploaded.Inc()
+}
+
+// FileRejected учитывает отклонённый файл с одной из причин Reject*
+func (m *Metrics) FileRejected(reason string) {
+	if m == nil {
+		return
+	}
+	m.filesRejected.WithLabelValues(reason).Inc()
+}
+
+// BytesDownloaded учитывает скачанные байты
+func (m *Metrics) BytesDownloaded(n int64) {
+	if m == nil {
+		return
+	}
+	m.bytesDownloaded.Add(float64(n))
+}
+
+// ParseFailed учитывает ошибку парсинга; формат определяется по расширению файла
+func (m *Metrics) ParseFailed(filePath string) {
+	if m == nil {
+		return
+	}
+	m.parseFailures.WithLabelValues(FileFormat(filePath)).Inc()
+}
+
+// ProcessingFinished учитывает длительность обработки, начатой в start
+func (m *Metrics) ProcessingFinished(start time.Time) {
+	if m == nil {
+		return
+	}
+	m.processingDuration.Observe(time.Since(start).Seconds())
+}
+
+// ResultParticipants учитывает количество участников в результате
+func (m *Metrics) ResultParticipants(n int) {
+	if m == nil {
+		return
+	}
+	m.participants.Observe(float64(n))
+}
+
+// APIError учитывает ошибку запроса к Bot API
+func (m *Metrics) APIError(code string) {
+	if m == nil {
+		return
+	}
+	m.apiErrors.WithLabelValues(code).Inc()
+}
+
+// FileFormat возвращает формат экспорта для метки: json, html или other
+func FileFormat(filePath string) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+		return "json"
+	case ".html", ".htm":
+		return "html"
+	default:
+		return "other"
+	}
+}
+
+// AccessDenied учитывает сообщение, отклонённое политикой доступа
+func (m *Metrics) AccessDenied(reason string) {
+	if m == nil {
+		return
+	}
+	m.accessDenied.WithLabelValues(reason).Inc()
+}
+
+// QuotaRejected учитывает действие, отклонённое квотой или ограничением частоты
+func (m *Metrics) QuotaRejected(violation string) {
+	if m == nil {
+		return
+	}
+	m.quotaRejections.WithLabelValues(violation).Inc()
+}
+
+// UserBanned учитывает временную блокировку пользователя
+func (m *Metrics) UserBanned() {
+	if m == nil {
+		return
+	}
+	m.bans.Inc()
+}
@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Экспортёры спанов
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// DefaultServiceName имя сервиса в спанах по умолчанию
+const DefaultServiceName = "tg-export-bot"
+
+// Config параметры трассировки
+type Config struct {
+	// Exporter none (по умолчанию), stdout или otlp
+	Exporter string
+	// Endpoint адрес OTLP/HTTP коллектора, например http://localhost:4318;
+	// пустая строка — адрес из OTEL_EXPORTER_OTLP_ENDPOINT или localhost:4318
+	Endpoint string
+	// ServiceName имя сервиса в ресурсе спанов
+	ServiceName string
+	// SampleRatio доля записываемых трасс от 0 до 1: 0 — не записывать, 1 и больше — записывать все
+	SampleRatio float64
+	// Output приёмник для экспортёра stdout; по умолчанию os.Stdout
+	Output io.Writer
+}
+
+// Setup настраивает глобальный TracerProvider. Пакеты получают трассировщик через otel.Tracer,
+// поэтому без вызова Setup (или с Exporter=none) спаны не создаются и ничего не стоят.
+// Возвращаемая функция отправляет накопленные спаны и должна быть вызвана при завершении.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	var exporter sdktrace.SpanExporter
+	switch strings.ToLower(strings.TrimSpace(cfg.Exporter)) {
+	case "", ExporterNone:
+		return noop, nil
+	case ExporterStdout:
+		out := cfg.Output
+		if out == nil {
+			out = os.Stdout
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(out))
+		if err != nil {
+			return noop, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		exporter = exp
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		exp, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return noop, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		exporter = exp
+	default:
+		return noop, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return noop, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	var sampler sdktrace.Sampler
+	switch {
+	case cfg.SampleRatio <= 0:
+		sampler = sdktrace.NeverSample()
+	case cfg.SampleRatio >= 1:
+		sampler = sdktrace.AlwaysSample()
+	default:
+		sampler = sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown, nil
+}